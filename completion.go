@@ -0,0 +1,137 @@
+package main
+
+import (
+  "flag"
+  "fmt"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+
+  . "github.com/mesosphere-incubator/terraform-wheels/plugins"
+)
+
+/**
+ * Returns the names of the global wheels flags, with their `--` prefix
+ */
+func getWheelsFlagNames() []string {
+  var names []string
+  createWheelsFlags(&WheelsOptions{}).VisitAll(func(f *flag.Flag) {
+    names = append(names, "--"+f.Name)
+  })
+  return names
+}
+
+func getCommandNames(commands []CommandInfo) []string {
+  var names []string
+  for _, cmd := range commands {
+    names = append(names, cmd.Name)
+  }
+  return names
+}
+
+func getSortedSubCommands() []string {
+  var names []string
+  for name := range terraformSubCommands {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  return names
+}
+
+func printBashCompletion(prog string, commands []CommandInfo) {
+  fn := "_" + strings.ReplaceAll(prog, "-", "_")
+
+  fmt.Printf("# bash completion for %s\n", prog)
+  fmt.Printf("# Install with: source <(%s wheels-completion bash)\n", prog)
+  fmt.Printf("%s() {\n", fn)
+  fmt.Printf("  local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+  fmt.Printf("  local cmd=\"\"\n")
+  fmt.Printf("  local i\n")
+  fmt.Printf("  for ((i=1; i < COMP_CWORD; i++)); do\n")
+  fmt.Printf("    if [[ \"${COMP_WORDS[i]}\" != -* ]]; then cmd=\"${COMP_WORDS[i]}\"; break; fi\n")
+  fmt.Printf("  done\n")
+  fmt.Printf("  if [[ \"$cur\" == --wheels-* ]]; then\n")
+  fmt.Printf("    COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n", strings.Join(getWheelsFlagNames(), " "))
+  fmt.Printf("    return\n")
+  fmt.Printf("  fi\n")
+  fmt.Printf("  case \"$cmd\" in\n")
+  fmt.Printf("    \"\"|help)\n")
+  fmt.Printf("      COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") ) ;;\n", strings.Join(getCommandNames(commands), " "))
+  for _, name := range getSortedSubCommands() {
+    fmt.Printf("    %s)\n", name)
+    fmt.Printf("      COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") ) ;;\n", strings.Join(terraformSubCommands[name], " "))
+  }
+  fmt.Printf("    *)\n")
+  fmt.Printf("      COMPREPLY=( $(compgen -f -- \"$cur\") ) ;;\n")
+  fmt.Printf("  esac\n")
+  fmt.Printf("}\n")
+  fmt.Printf("complete -o filenames -F %s %s\n", fn, prog)
+}
+
+func printZshCompletion(prog string, commands []CommandInfo) {
+  fn := "_" + strings.ReplaceAll(prog, "-", "_")
+
+  fmt.Printf("#compdef %s\n", prog)
+  fmt.Printf("# Install with: %s wheels-completion zsh > \"${fpath[1]}/_%s\"\n", prog, prog)
+  fmt.Printf("%s() {\n", fn)
+  fmt.Printf("  local -a commands\n")
+  fmt.Printf("  commands=(\n")
+  for _, cmd := range commands {
+    fmt.Printf("    %s\n", quoteShell(cmd.Name+":"+cmd.Description))
+  }
+  fmt.Printf("  )\n")
+  fmt.Printf("  if [[ \"$PREFIX\" == --wheels-* ]]; then\n")
+  fmt.Printf("    compadd -- %s\n", strings.Join(getWheelsFlagNames(), " "))
+  fmt.Printf("  elif (( CURRENT == 2 )) || [[ \"${words[2]}\" == help && CURRENT == 3 ]]; then\n")
+  fmt.Printf("    _describe -t commands 'command' commands\n")
+  fmt.Printf("  else\n")
+  fmt.Printf("    case \"${words[2]}\" in\n")
+  for _, name := range getSortedSubCommands() {
+    fmt.Printf("      %s) (( CURRENT == 3 )) && compadd -- %s || _files ;;\n", name, strings.Join(terraformSubCommands[name], " "))
+  }
+  fmt.Printf("      *) _files ;;\n")
+  fmt.Printf("    esac\n")
+  fmt.Printf("  fi\n")
+  fmt.Printf("}\n")
+  fmt.Printf("compdef %s %s\n", fn, prog)
+}
+
+func printFishCompletion(prog string, commands []CommandInfo) {
+  fmt.Printf("# fish completion for %s\n", prog)
+  fmt.Printf("# Install with: %s wheels-completion fish > ~/.config/fish/completions/%s.fish\n", prog, prog)
+  for _, cmd := range commands {
+    fmt.Printf("complete -c %s -f -n '__fish_use_subcommand' -a %s -d %s\n", prog, cmd.Name, quoteShell(cmd.Description))
+  }
+  for _, name := range getSortedSubCommands() {
+    fmt.Printf("complete -c %s -f -n '__fish_seen_subcommand_from %s' -a %s\n", prog, name, quoteShell(strings.Join(terraformSubCommands[name], " ")))
+  }
+  for _, name := range getWheelsFlagNames() {
+    fmt.Printf("complete -c %s -l %s\n", prog, strings.TrimPrefix(name, "--"))
+  }
+}
+
+func quoteShell(s string) string {
+  return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+/**
+ * Prints the completion script for the given shell
+ */
+func printCompletion(shell string, plugins []Plugin) error {
+  prog := filepath.Base(os.Args[0])
+  commands := GetAllCommands(plugins)
+
+  switch shell {
+  case "bash":
+    printBashCompletion(prog, commands)
+  case "zsh":
+    printZshCompletion(prog, commands)
+  case "fish":
+    printFishCompletion(prog, commands)
+  default:
+    return fmt.Errorf("Unsupported shell '%s', please use one of: bash, zsh, fish", shell)
+  }
+
+  return nil
+}
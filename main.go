@@ -1,6 +1,7 @@
 package main
 
 import (
+  "flag"
   "fmt"
   "os"
   "strings"
@@ -15,17 +16,18 @@ var buildVersion string // Defined at build time
 
 var plugins []Plugin = []Plugin{
   CreatePluginImportCluster(),
+  CreatePluginExportLaunchConfig(),
   CreatePluginDcosAws(),
+  CreatePluginDcosAzure(),
+  CreatePluginDcosGcp(),
   CreatePluginSSHAgent(),
   CreatePluginAddService(),
   CreatePluginDcosProvider(),
-}
-
-var knownTerraformCommands []string = []string{
-  "apply", "console", "destroy", "env", "fmt", "get", "graph", "import", "init",
-  "output", "plan", "providers", "push", "refresh", "show", "taint", "untaint",
-  "validate", "version", "workspace", "0.12checklist", "debug", "force-unlock",
-  "state",
+  CreatePluginWheelsConfig(),
+  CreatePluginDoctor(),
+  CreatePluginUpdateCluster(),
+  CreatePluginBackend(),
+  CreatePluginWheelsCost(),
 }
 
 func showMissingTerraformHelp() {
@@ -41,29 +43,27 @@ func showMissingTerraformHelp() {
 func showPluginHelp() {
   fmt.Println("")
   fmt.Println("DC/OS Commands:")
-  fmt.Printf("    %-18s %s %s\n", "wheels-version", "Check the version of", os.Args[0])
-  fmt.Printf("    %-18s %s %s\n", "wheels-upgrade", "Upgrade to the latest version of", os.Args[0])
+  for _, cmd := range builtinCommands {
+    fmt.Printf("    %-18s %s\n", cmd.Name, cmd.Description)
+  }
 
   for _, plugin := range plugins {
     for _, cmd := range plugin.GetCommands() {
       fmt.Printf("    %-18s %s\n", cmd.GetName(), cmd.GetDescription())
     }
   }
+
+  fmt.Println("")
+  fmt.Println("Global wheels flags:")
+  createWheelsFlags(&WheelsOptions{}).VisitAll(func(f *flag.Flag) {
+    fmt.Printf("    --%-16s %s\n", f.Name, f.Usage)
+  })
 }
 
 func showInitUsage() {
   FatalError(fmt.Errorf("Your current directory does not contain terraform files. Please run `init` to prepare it."))
 }
 
-func shouldShowHelp(args []string) bool {
-  for _, arg := range args {
-    if strings.Contains(arg, "help") {
-      return true
-    }
-  }
-  return false
-}
-
 func showHelp(sandbox *ProjectSandbox) {
   // Show terraform help
   if sandbox.HasTerraform() {
@@ -81,6 +81,44 @@ func showHelp(sandbox *ProjectSandbox) {
   os.Exit(1)
 }
 
+/**
+ * Shows the help page of a single command
+ */
+func showCommandHelp(sandbox *ProjectSandbox, name string) {
+  for _, cmd := range builtinCommands {
+    if cmd.Name == name {
+      usage := ""
+      if name == "wheels-completion" {
+        usage = " bash|zsh|fish"
+      }
+      fmt.Printf("Usage: %s %s%s\n", os.Args[0], Bold(name), usage)
+      fmt.Println("")
+      fmt.Println(cmd.Description)
+      return
+    }
+  }
+
+  // Plugin commands print their own help page
+  if cmd := FindPluginCommand(plugins, name); cmd != nil {
+    err := cmd.Handle([]string{"-help"}, sandbox, nil)
+    if err != nil {
+      FatalError(err)
+    }
+    return
+  }
+
+  if IsTerraformCommand(name) {
+    tf, err := sandbox.GetTerraform()
+    if err != nil {
+      FatalError(err)
+    }
+    tf.Invoke([]string{name, "-help"})
+    return
+  }
+
+  FatalError(fmt.Errorf("Unknown command '%s'", name))
+}
+
 func invokeTerraform(sandbox *ProjectSandbox, tf *TerraformWrapper, plugins []Plugin, args []string) {
   isInit := false
   for _, arg := range args {
@@ -90,24 +128,62 @@ func invokeTerraform(sandbox *ProjectSandbox, tf *TerraformWrapper, plugins []Pl
     }
   }
 
-  // Pre-run
-  for _, plugin := range plugins {
-    err := plugin.BeforeRun(sandbox, tf, isInit)
-    if err != nil {
-      FatalError(fmt.Errorf("Could not start %s: %s", plugin.GetName(), err.Error()))
+  command := ""
+  for _, arg := range args {
+    if !strings.HasPrefix(arg, "-") {
+      command = arg
+      break
     }
   }
 
-  // Run
-  err := tf.Invoke(args)
+  tf.SetCommand(command, args)
 
-  // Post-run
-  for _, plugin := range plugins {
-    perr := plugin.AfterRun(sandbox, tf, err)
-    if perr != nil {
-      FatalError(fmt.Errorf("Could not finalize %s: %s", plugin.GetName(), err.Error()))
+  // The plugins are not started either, since they have side effects (ex.
+  // the ssh-agent plugin starts an agent and loads the keys)
+  if sandbox.IsDryRun() {
+    PrintInfo("Dry-run: not running terraform %s", strings.Join(args, " "))
+    return
+  }
+
+  // Make sure that every started plugin is also stopped
+  lifecycle := CreatePluginLifecycle(sandbox, tf)
+  defer lifecycle.Recover()
+
+  err := lifecycle.Start(plugins, isInit)
+  if err != nil {
+    FatalError(err)
+  }
+
+  // A failing pre-hook vetoes the terraform run
+  var tfErr error
+  hookErr := lifecycle.RunHooks(command, "pre")
+  if hookErr == nil {
+    tfErr = lifecycle.Run(args)
+    if tfErr == nil {
+      hookErr = lifecycle.RunHooks(command, "post")
     }
   }
+
+  // The plugins see a vetoed run as a failed one
+  runErr := tfErr
+  if runErr == nil {
+    runErr = hookErr
+  }
+  err = lifecycle.Stop(runErr)
+  if err != nil {
+    FatalError(err)
+  }
+  if tfErr != nil {
+    FatalError(tfErr)
+  }
+  if hookErr != nil {
+    FatalError(hookErr)
+  }
+
+  // Propagate the exit code of terraform
+  if code := tf.GetExitCode(); code != 0 {
+    Exit(code)
+  }
 }
 
 func loadPlugins(sandbox *ProjectSandbox) []Plugin {
@@ -117,66 +193,125 @@ func loadPlugins(sandbox *ProjectSandbox) []Plugin {
     if err != nil {
       FatalError(err)
     }
+    if sandbox.GetConfig().IsPluginEnabled(plugin.GetName()) {
+      used = true
+    }
 
     if used {
-      PrintInfo("Using plugin %s", plugin.GetName())
       loadedPlugins = append(loadedPlugins, plugin)
     }
   }
 
+  // Order the plugins according to their dependencies
+  loadedPlugins, err := SortPlugins(loadedPlugins)
+  if err != nil {
+    FatalError(err)
+  }
+  for _, plugin := range loadedPlugins {
+    PrintInfo("Using plugin %s", plugin.GetName())
+  }
+
   return loadedPlugins
 }
 
 func main() {
+  cmdline, err := ParseCommandLine(os.Args[1:])
+  if err != nil {
+    FatalError(err)
+  }
+  err = SetOutputFormat(wheelsOptions.Output)
+  if err != nil {
+    FatalError(err)
+  }
+
   // Early upgrade checks
-  if len(os.Args) > 1 {
-    cmd := os.Args[1]
+  switch cmdline.Command {
+  case "wheels-complete-upgrade":
+    if len(cmdline.Args) < 1 {
+      FatalError(fmt.Errorf("Missing the path to the previous version"))
+    }
+    PrintInfo("🍺 Upgraded to latest version")
+    CompleteUpgrade(cmdline.Args[0])
+    return
 
-    if cmd == "wheels-complete-upgrade" {
-      PrintInfo("🍺 Upgraded to latest version")
-      CompleteUpgrade(os.Args[2])
-      return
+  case "wheels-version":
+    PrintInfo("You are using terraform-wheels version %s", Bold(buildVersion))
+    return
 
-    } else if cmd == "wheels-version" {
-      PrintInfo("You are using terraform-wheels version %s", Bold(buildVersion))
-      return
+  case "wheels-upgrade":
+    ver := semver.MustParse(buildVersion)
+    latest, err := GetLatestVersion()
+    if err != nil {
+      FatalError(err)
+    }
 
-    } else if cmd == "wheels-upgrade" {
-      ver := semver.MustParse(buildVersion)
-      latest, err := GetLatestVersion()
+    if latest.Version.Compare(ver) > 0 {
+      PrintInfo("Upgrading from %s to %s", Bold(buildVersion), Bold(latest.Version.String()))
+      err = PerformUpgrade(latest)
       if err != nil {
         FatalError(err)
       }
-
-      if latest.Version.Compare(ver) > 0 {
-        PrintInfo("Upgrading from %s to %s", Bold(buildVersion), Bold(latest.Version.String()))
-        err = PerformUpgrade(latest)
-        if err != nil {
-          FatalError(err)
-        }
-      } else {
-        PrintInfo("You are running the latest released version")
-      }
-      return
+    } else {
+      PrintInfo("You are running the latest released version")
     }
+    return
   }
 
   // Get a work directory sandbox
+  if wheelsOptions.Chdir != "" {
+    err = os.Chdir(wheelsOptions.Chdir)
+    if err != nil {
+      FatalError(fmt.Errorf("Could not change to the project directory: %s", err.Error()))
+    }
+  }
   cwd, err := os.Getwd()
   if err != nil {
     FatalError(err)
   }
   sandbox, err := OpenSandbox(cwd)
   if err != nil {
-    FatalError(err)
+    if !IsDiagnosticCommand(cmdline.Command) {
+      FatalError(err)
+    }
+    sandbox, err = OpenSandboxLenient(cwd)
+    if err != nil {
+      FatalError(err)
+    }
   }
+  sandbox.SetDryRun(wheelsOptions.DryRun)
+  sandbox.SetBackup(wheelsOptions.Backup)
+
+  // Load the external plugins found in the plugin directories and drop the
+  // ones disabled in the configuration
+  var enabledPlugins []Plugin
+  for _, plugin := range append(plugins, DiscoverExternalPlugins(sandbox)...) {
+    if !sandbox.GetConfig().IsPluginDisabled(plugin.GetName()) {
+      enabledPlugins = append(enabledPlugins, plugin)
+    }
+  }
+  plugins = enabledPlugins
 
   // Handle help prompt early
-  if len(os.Args) <= 1 || strings.Contains(os.Args[1], "help") {
+  if cmdline.IsHelp() {
+    if cmdline.Command == "help" && len(cmdline.Args) > 0 {
+      showCommandHelp(sandbox, cmdline.Args[0])
+      return
+    }
     showHelp(sandbox)
     return
   }
 
+  if cmdline.Command == "wheels-completion" {
+    if len(cmdline.Args) < 1 {
+      FatalError(fmt.Errorf("Please specify the shell: bash, zsh or fish"))
+    }
+    err = printCompletion(cmdline.Args[0], plugins)
+    if err != nil {
+      FatalError(err)
+    }
+    return
+  }
+
   // Check the sandbox status
   hasTfFiles, err := sandbox.HasTerraformFiles()
   if err != nil {
@@ -184,80 +319,49 @@ func main() {
   }
 
   // Check if this is a plugin command and delegate it to the respective handler
-  if len(os.Args) > 1 {
-
-    // Check if we were invoked a valid terraform command
-    isTerraformCommand := false
-    for _, cmd := range knownTerraformCommands {
-      for _, arg := range os.Args[1:] {
-        if arg == cmd {
-          isTerraformCommand = true
-          break
-        }
+  if cmd := FindPluginCommand(plugins, cmdline.Command); cmd != nil {
+    // The diagnostic commands check terraform themselves, without
+    // downloading it
+    var tf *TerraformWrapper
+    if !IsDiagnosticCommand(cmdline.Command) {
+      tf, err = sandbox.GetTerraform()
+      if err != nil {
+        FatalError(err)
       }
     }
 
-    // Ignore flags until we find a command
-    cmd_n := ""
-    cmd_i := 0
-    for i := 1; i < len(os.Args); i++ {
-      if strings.HasPrefix(os.Args[i], "-") {
-        continue
-      }
-      cmd_i = i
-      cmd_n = os.Args[i]
-      break
+    SetEventPlugin(FindCommandPlugin(plugins, cmdline.Command).GetName())
+    err = cmd.Handle(cmdline.Args, sandbox, tf)
+    SetEventPlugin("")
+    if err != nil {
+      FatalError(err)
     }
 
-    // If there was no command, show help
-    if cmd_i == 0 {
-      showHelp(sandbox)
-      return
+    nowHasTfFiles, err := sandbox.HasTerraformFiles()
+    if err != nil {
+      FatalError(err)
     }
 
-    // Check if this is a plugin command
-    for _, plugin := range plugins {
-      for _, cmd := range plugin.GetCommands() {
-        if cmd.GetName() == cmd_n {
-          tf, err := sandbox.GetTerraform()
-          if err != nil {
-            FatalError(err)
-          }
-
-          err = cmd.Handle(os.Args[2:], sandbox, tf)
-          if err != nil {
-            FatalError(err)
-          }
-
-          nowHasTfFiles, err := sandbox.HasTerraformFiles()
-          if err != nil {
-            FatalError(err)
-          }
-
-          // If that's the first time we saw some tf files, take the opportunity
-          // to run initialize, so the user has less things to do
-          if !hasTfFiles && nowHasTfFiles {
-            PrintInfo("Terraform project created, initializing now")
-
-            err := sandbox.ReloadTerraformProject()
-            if err != nil {
-              FatalError(err)
-            }
-
-            loadedPlugins := loadPlugins(sandbox)
-            invokeTerraform(sandbox, tf, loadedPlugins, []string{"init"})
-          }
-
-          return
-        }
+    // If that's the first time we saw some tf files, take the opportunity
+    // to run initialize, so the user has less things to do
+    if !hasTfFiles && nowHasTfFiles {
+      PrintInfo("Terraform project created, initializing now")
+
+      err := sandbox.ReloadTerraformProject()
+      if err != nil {
+        FatalError(err)
       }
-    }
 
-    // Check if this is not a terraform command neither
-    if !isTerraformCommand {
-      showHelp(sandbox)
-      return
+      loadedPlugins := loadPlugins(sandbox)
+      invokeTerraform(sandbox, tf, loadedPlugins, []string{"init"})
     }
+
+    return
+  }
+
+  // Everything else must be a terraform command
+  if cmdline.Command != "" && !IsTerraformCommand(cmdline.Command) {
+    FatalError(fmt.Errorf("Unknown command '%s'. Use `%s help` to see all the available commands", cmdline.Command, os.Args[0]))
   }
 
   // Initialize terraform now
@@ -268,12 +372,12 @@ func main() {
 
   // Forward to terraform
   loadedPlugins := loadPlugins(sandbox)
-  invokeTerraform(sandbox, tf, loadedPlugins, os.Args[1:])
+  invokeTerraform(sandbox, tf, loadedPlugins, cmdline.TerraformArgs)
 
-  if !hasTfFiles {
+  if !hasTfFiles && !IsJSONOutput() {
     fmt.Println("")
-    fmt.Printf("Consider running %s add-aws-cluster if you are trying to\n", os.Args[0])
-    fmt.Printf("launch a DC/OS cluster. Or %s -help to see all options\n", os.Args[0])
+    fmt.Printf("Consider running %s add-aws-cluster (or add-azure-cluster, add-gcp-cluster)\n", os.Args[0])
+    fmt.Printf("if you are trying to launch a DC/OS cluster. Or %s -help to see all options\n", os.Args[0])
   }
 
 }
@@ -0,0 +1,241 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+  "path/filepath"
+  "strings"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+type PluginBackend struct {
+}
+
+func CreatePluginBackend() *PluginBackend {
+  return &PluginBackend{}
+}
+
+func (p *PluginBackend) GetName() string {
+  return "backend"
+}
+
+func (p *PluginBackend) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginBackend) GetPriority() int {
+  return 0
+}
+
+func (p *PluginBackend) IsUsed(project *ProjectSandbox) (bool, error) {
+  return false, nil
+}
+
+func (p *PluginBackend) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  return nil
+}
+
+func (p *PluginBackend) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  return nil
+}
+
+func (p *PluginBackend) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginBackendCmdAddBackend{},
+  }
+}
+
+type PluginBackendCmdAddBackend struct {
+}
+
+func (p *PluginBackendCmdAddBackend) GetName() string {
+  return "add-backend"
+}
+
+func (p *PluginBackendCmdAddBackend) GetDescription() string {
+  return "Configures where terraform keeps the state of the project"
+}
+
+func (p *PluginBackendCmdAddBackend) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  var fileName string = "backend.tf"
+
+  config := project.GetConfig()
+  projectName := filepath.Base(project.GetBaseDir())
+
+  fSet := flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  fType := fSet.String("type", "", "The type of the backend: s3, consul, http or local")
+  fBucket := fSet.String("bucket", "", "[s3] The name of the bucket")
+  fKey := fSet.String("key", fmt.Sprintf("terraform-wheels/%s/terraform.tfstate", projectName), "[s3] The path of the state in the bucket")
+  fRegion := fSet.String("region", config.GetRegion(), "[s3] The region of the bucket")
+  fTable := fSet.String("dynamodb_table", "", "[s3] The DynamoDB table to use for locking the state")
+  fCreate := fSet.Bool("create", false, "[s3] Create the bucket and the DynamoDB table if they do not exist")
+  fAddress := fSet.String("address", "", "[consul, http] The address of the consul agent, or the URL of the state")
+  fLockAddress := fSet.String("lock_address", "", "[http] The URL for locking the state")
+  fUnlockAddress := fSet.String("unlock_address", "", "[http] The URL for unlocking the state")
+  fPath := fSet.String("path", "", "[consul] The path of the state in the KV store (default terraform-wheels/<project>), [local] The path of the state file")
+  fNoMigrate := fSet.Bool("no-migrate", false, "Do not run `terraform init` to migrate the existing state to the backend")
+  help := fSet.Bool("help", false, "Show this help message")
+  fSet.BoolVar(help, "h", false, "Show this help message")
+  err := fSet.Parse(args)
+  if err != nil {
+    return err
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), "", []interface{}{
+      fmt.Sprintf("This command will generate a '%s' file in the project directory that", fileName),
+      "configures where terraform keeps the state of the project, and migrate the",
+      "existing state there.",
+    }, fSet)
+    return nil
+  }
+
+  // Only one backend is allowed in the project
+  for _, existing := range project.GetTerraformResourcesMatchingName("terraform", "backend") {
+    if file, ok := existing["_file"].(string); ok && file != fileName {
+      return fmt.Errorf("The project already has a backend in %s, remove it first", file)
+    }
+  }
+
+  settings := make(map[string]string)
+  switch *fType {
+  case "s3":
+    if *fBucket == "" {
+      return fmt.Errorf("Please specify the bucket with -bucket")
+    }
+    settings["bucket"] = *fBucket
+    settings["key"] = *fKey
+    settings["region"] = *fRegion
+    settings["encrypt"] = "true"
+    if *fTable != "" {
+      settings["dynamodb_table"] = *fTable
+    }
+
+    if *fCreate {
+      err = p.createS3Backend(project, *fRegion, *fBucket, *fTable)
+      if err != nil {
+        return err
+      }
+    }
+
+  case "consul":
+    settings["path"] = *fPath
+    if *fPath == "" {
+      settings["path"] = fmt.Sprintf("terraform-wheels/%s", projectName)
+    }
+    if *fAddress != "" {
+      settings["address"] = *fAddress
+    }
+
+  case "http":
+    if *fAddress == "" {
+      return fmt.Errorf("Please specify the URL of the state with -address")
+    }
+    settings["address"] = *fAddress
+    if *fLockAddress != "" {
+      settings["lock_address"] = *fLockAddress
+    }
+    if *fUnlockAddress != "" {
+      settings["unlock_address"] = *fUnlockAddress
+    }
+
+  case "local":
+    if *fPath != "" {
+      settings["path"] = *fPath
+    }
+
+  case "":
+    return fmt.Errorf("Please specify the type of the backend with -type")
+  default:
+    return fmt.Errorf("Unsupported backend type '%s', we only support: s3, consul, http, local", *fType)
+  }
+
+  if *fCreate && *fType != "s3" {
+    return fmt.Errorf("The -create flag is only supported with the s3 backend")
+  }
+
+  lines := []string{
+    `terraform {`,
+    fmt.Sprintf(`  backend "%s" {`, *fType),
+  }
+  for _, k := range sortedStringKeys(settings) {
+    if k == "encrypt" {
+      lines = append(lines, fmt.Sprintf(`    %s = %s`, k, settings[k]))
+    } else {
+      lines = append(lines, fmt.Sprintf(`    %s = %s`, k, FormatJSON(settings[k])))
+    }
+  }
+  lines = append(lines, `  }`, `}`)
+
+  PrintInfo("%s%s%s", Bold("Writing "), Bold(Green(fileName)), Bold(fmt.Sprintf(" containing the %s backend configuration", *fType)))
+  err = project.WriteFormattedTerraformFile(fileName, []byte(strings.Join(lines, "\n")))
+  if err != nil {
+    return err
+  }
+
+  return p.migrateState(project, tf, *fNoMigrate)
+}
+
+func (p *PluginBackendCmdAddBackend) createS3Backend(project *ProjectSandbox, region string, bucket string, table string) error {
+  if project.IsDryRun() {
+    PrintInfo("Dry-run: not creating the bucket %s and the lock table", bucket)
+    return nil
+  }
+
+  created, err := CreateS3StateBucket(region, bucket)
+  if err != nil {
+    return err
+  }
+  if created {
+    PrintInfo("Created the versioned and encrypted S3 bucket %s in %s", Bold(bucket), region)
+  } else {
+    PrintInfo("Using the existing S3 bucket %s", Bold(bucket))
+  }
+
+  if table == "" {
+    return nil
+  }
+  created, err = CreateDynamoDBLockTable(region, table)
+  if err != nil {
+    return err
+  }
+  if created {
+    PrintInfo("Created the DynamoDB lock table %s in %s", Bold(table), region)
+  } else {
+    PrintInfo("Using the existing DynamoDB lock table %s", Bold(table))
+  }
+  return nil
+}
+
+/**
+ * Re-initializes an already initialized project, so that terraform 0.11
+ * copies the existing state to the new backend without asking
+ */
+func (p *PluginBackendCmdAddBackend) migrateState(project *ProjectSandbox, tf *TerraformWrapper, noMigrate bool) error {
+  // The .terraform directory alone is not a state (ex. only the modules and
+  // the plugins were downloaded), so look for the state of a backend or the
+  // local state
+  if !project.HasFile(filepath.Join(".terraform", "terraform.tfstate")) && !project.HasFile("terraform.tfstate") {
+    // The project will be initialized with the backend from the beginning
+    return nil
+  }
+
+  if noMigrate || project.IsDryRun() {
+    PrintInfo("Run %s to migrate the existing state to the backend", Bold("terraform-wheels init -force-copy"))
+    return nil
+  }
+
+  PrintInfo("Migrating the existing state to the backend")
+  err := tf.Invoke([]string{"init", "-force-copy", "-input=false"})
+  if err != nil {
+    return err
+  }
+  if tf.GetExitCode() != 0 {
+    return fmt.Errorf("Could not migrate the state, terraform exited with code %d", tf.GetExitCode())
+  }
+
+  PrintInfo("The state was migrated. Once you have verified it, you can delete the local %s files", Bold("terraform.tfstate*"))
+  return nil
+}
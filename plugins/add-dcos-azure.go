@@ -0,0 +1,239 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+type PluginDcosAzure struct {
+  showInstructions bool
+  createdFile      string
+}
+
+func CreatePluginDcosAzure() *PluginDcosAzure {
+  return &PluginDcosAzure{false, ""}
+}
+
+func (p *PluginDcosAzure) GetName() string {
+  return "dcos-azure"
+}
+
+func (p *PluginDcosAzure) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginDcosAzure) GetPriority() int {
+  return 0
+}
+
+func (p *PluginDcosAzure) IsUsed(project *ProjectSandbox) (bool, error) {
+  // Check if we are using the Azure provider
+  mods := project.GetTerraformResourcesMatching("module", "source", dcosModuleSources["azurerm"])
+  return mods != nil, nil
+}
+
+func (p *PluginDcosAzure) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  if !IsAzureCredsOK() {
+    err := fmt.Errorf("Could not find Azure credentials in your environment. Use `az login`, or export the ARM_CLIENT_ID, ARM_CLIENT_SECRET, ARM_SUBSCRIPTION_ID and ARM_TENANT_ID of a service principal")
+    if initRun {
+      PrintWarning(err.Error())
+    } else {
+      FatalError(err)
+    }
+  }
+
+  return nil
+}
+
+func (p *PluginDcosAzure) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  if p.showInstructions {
+    printClusterInstructions("Microsoft Azure", p.createdFile)
+  }
+  return nil
+}
+
+func (p *PluginDcosAzure) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginDcosAzureCmdAddCluster{p},
+  }
+}
+
+/**
+ * The types of the parameters of the Azure cluster module that are validated
+ */
+func azureClusterParams() map[string]ParamSpec {
+  return map[string]ParamSpec{
+    "num_masters":                OddIntParam(1),
+    "num_private_agents":         IntParam(0),
+    "num_public_agents":          IntParam(0),
+    "bootstrap_disk_size":        IntParam(1),
+    "masters_disk_size":          IntParam(1),
+    "private_agents_disk_size":   IntParam(1),
+    "public_agents_disk_size":    IntParam(1),
+    "cluster_name_random_string": BoolParam(),
+    "admin_ips":                  CIDRParam(),
+    "accepted_internal_networks": CIDRParam(),
+    "subnet_range":               CIDRParam(),
+    "dcos_variant":               EnumParam("open", "ee"),
+    "dcos_security":              EnumParam("strict", "permissive", "disabled"),
+  }
+}
+
+type PluginDcosAzureCmdAddCluster struct {
+  parent *PluginDcosAzure
+}
+
+func (p *PluginDcosAzureCmdAddCluster) GetName() string {
+  return "add-azure-cluster"
+}
+
+func (p *PluginDcosAzureCmdAddCluster) GetDescription() string {
+  return "Adds a configuration file to deploy a DC/OS cluster on Azure"
+}
+
+func (p *PluginDcosAzureCmdAddCluster) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  var tfc TerraformFileConfig
+
+  config := project.GetConfig()
+
+  tfc.Flags = flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  fPassword := tfc.Flags.String("dcos_superuser_password", "", "The plain-text password to encode")
+  fOwner := tfc.Flags.String("owner", config.GetOwner(), "The user-name that owns this cluster")
+  fExpire := tfc.Flags.String("expiration", config.GetExpiration(), "How long to keep the cluster running before cloud-cleaner tears it down")
+  fName := tfc.Flags.String("name", "", "A unique id of the cluster, to deploy multiple clusters in the same project")
+
+  tfc.IgnoreFlags = []string{"owner", "expiration", "dcos_superuser_password", "name"}
+  tfc.Params = azureClusterParams()
+
+  // The rest of the flags are the variables of the module version to use.
+  // The help page does not go to the network, and shows the variables of the
+  // local module or of the snapshot.
+  loadVariables := LoadModuleVariables
+  latestVersion := func() string {
+    return GetLatestProviderModuleVersion("azurerm", "0.2.0")
+  }
+  if IsHelpRequested(args) {
+    loadVariables = LoadLocalModuleVariables
+    latestVersion = func() string {
+      return "0.2.0"
+    }
+  }
+  moduleVersion := config.GetModuleVersion("azurerm", latestVersion)
+  moduleVars, err := loadVariables(project, "azurerm", moduleVersion)
+  if err != nil {
+    return err
+  }
+  tfc.AddModuleVariables(moduleVars)
+
+  help := tfc.Flags.Bool("help", false, "Show this help message")
+  tfc.Flags.BoolVar(help, "h", false, "Show this help message")
+  err = tfc.Flags.Parse(args)
+  if err != nil {
+    FatalError(err)
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), "", []interface{}{
+      "This command will generate a 'cluster-azure.tf' file in the project directory\n",
+      "that describes a deployment of a DC/OS cluster on Azure. A file with sane defaults",
+      "is created for you. You can override the values with the following flags:",
+    }, &tfc)
+    return nil
+  }
+
+  if *fName != "" {
+    if err := validateClusterId(*fName); err != nil {
+      return err
+    }
+  }
+  names := getClusterNames("azure", *fName)
+  fileName := names.File
+  tfc.Block = []string{"module", names.Module}
+
+  // Hash password if given as hash input
+  if *fPassword != "" {
+    hash, err := hashSuperuserPassword(*fPassword)
+    if err != nil {
+      return err
+    }
+    tfc.Flags.Set("dcos_superuser_password_hash", hash)
+  }
+
+  tfc.PreLines = names.providerLines("azurerm")
+  tfc.PreLines = append(tfc.PreLines, []string{
+    ``,
+    `# Used to determine your public IP for forwarding rules`,
+    fmt.Sprintf(`data "http" "%s" {`, names.WhatIsMyIP),
+    `  url = "http://whatismyip.akamai.com/"`,
+    `}`,
+    ``,
+    fmt.Sprintf(`module "%s" {`, names.Module),
+    `  source  = "dcos-terraform/dcos/azurerm"`,
+    fmt.Sprintf(`  version = "~> %s"`, moduleVersion),
+    ``,
+    `  providers = {`,
+    fmt.Sprintf(`    azurerm = "%s"`, names.providerRef("azurerm")),
+    `  }`,
+    ``,
+  }...)
+  tfc.BodyLines = []string{
+    `  # Change your default location here`,
+    `  location = "West US 2"`,
+    ``,
+    fmt.Sprintf(`  cluster_name               = "%s"`, names.clusterName()),
+    `  cluster_name_random_string = true`,
+    fmt.Sprintf(`  ssh_public_key_file        = "%s"`, names.SSHKey),
+    fmt.Sprintf(`  admin_ips                  = ["${data.http.%s.body}/32"]`, names.WhatIsMyIP),
+    ``,
+    `  num_masters        = 1`,
+    `  num_private_agents = 1`,
+    `  num_public_agents  = 1`,
+    ``,
+    fmt.Sprintf(`  dcos_version = "%s"`, GetLatestDCOSVersion("open", "2.0.0")),
+    ``,
+    `  ## If you have a DC/OS enterprise license, comment-out the following`,
+    `  ## lines and create a file "license.txt" in your project directory `,
+    `  ## containing the contents of your DC/OS license:`,
+    ``,
+    `  # dcos_variant              = "ee"`,
+    `  # dcos_security             = "permissive"`,
+    `  # dcos_license_key_contents = "${file("./license.txt")}"`,
+    ``,
+    `  dcos_variant = "open"`,
+    ``,
+    `  ## (Optionally) Use different superuser credentials`,
+    `  # dcos_superuser_username      = "superuser-name"`,
+    `  # dcos_superuser_password_hash = "${file("./dcos_superuser_password_hash.sha512")}"`,
+    ``,
+    `  dcos_instance_os       = "centos_7.6"`,
+    `  bootstrap_vm_size      = "Standard_B2s"`,
+    `  masters_vm_size        = "Standard_D4s_v3"`,
+    `  private_agents_vm_size = "Standard_D4s_v3"`,
+    `  public_agents_vm_size  = "Standard_D4s_v3"`,
+  }
+  tfc.PostLines = []string{
+    ``,
+    `  tags = {`,
+    fmt.Sprintf(`    "expiration" = "%s"`, *fExpire),
+    fmt.Sprintf(`    "owner"      = %s`, FormatJSON(*fOwner)),
+  }
+  for _, k := range sortedStringKeys(config.Tags) {
+    tfc.PostLines = append(tfc.PostLines, fmt.Sprintf(`    %s = %s`, FormatJSON(k), FormatJSON(config.Tags[k])))
+  }
+  tfc.PostLines = append(tfc.PostLines, `  }`, `}`)
+  tfc.PostLines = append(tfc.PostLines, names.outputLines()...)
+
+  contents, err := tfc.Generate()
+  if err != nil {
+    return err
+  }
+
+  PrintInfo("%s%s%s", Bold("Writing "), Bold(Green(fileName)), Bold(" containing information for deploying a DC/OS cluster on Azure"))
+  p.parent.showInstructions = true
+  p.parent.createdFile = fileName
+
+  return project.WriteFormattedTerraformFile(fileName, contents)
+}
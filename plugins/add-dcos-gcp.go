@@ -0,0 +1,265 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+  "regexp"
+  "strings"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+type PluginDcosGcp struct {
+  showInstructions bool
+  createdFile      string
+}
+
+func CreatePluginDcosGcp() *PluginDcosGcp {
+  return &PluginDcosGcp{false, ""}
+}
+
+func (p *PluginDcosGcp) GetName() string {
+  return "dcos-gcp"
+}
+
+func (p *PluginDcosGcp) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginDcosGcp) GetPriority() int {
+  return 0
+}
+
+func (p *PluginDcosGcp) IsUsed(project *ProjectSandbox) (bool, error) {
+  // Check if we are using the GCP provider
+  mods := project.GetTerraformResourcesMatching("module", "source", dcosModuleSources["gcp"])
+  return mods != nil, nil
+}
+
+func (p *PluginDcosGcp) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  if !IsGCPCredsOK() {
+    err := fmt.Errorf("Could not find GCP credentials in your environment. Use `gcloud auth application-default login`, or export GOOGLE_APPLICATION_CREDENTIALS with the path to a service account key")
+    if initRun {
+      PrintWarning(err.Error())
+    } else {
+      FatalError(err)
+    }
+  }
+
+  return nil
+}
+
+func (p *PluginDcosGcp) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  if p.showInstructions {
+    printClusterInstructions("Google Cloud Platform", p.createdFile)
+  }
+  return nil
+}
+
+func (p *PluginDcosGcp) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginDcosGcpCmdAddCluster{p},
+  }
+}
+
+/**
+ * The types of the parameters of the GCP cluster module that are validated
+ */
+func gcpClusterParams() map[string]ParamSpec {
+  return map[string]ParamSpec{
+    "num_masters":                OddIntParam(1),
+    "num_private_agents":         IntParam(0),
+    "num_public_agents":          IntParam(0),
+    "bootstrap_disk_size":        IntParam(1),
+    "masters_disk_size":          IntParam(1),
+    "private_agents_disk_size":   IntParam(1),
+    "public_agents_disk_size":    IntParam(1),
+    "cluster_name_random_string": BoolParam(),
+    "admin_ips":                  CIDRParam(),
+    "accepted_internal_networks": CIDRParam(),
+    "master_cidr_range":          CIDRParam(),
+    "agent_cidr_range":           CIDRParam(),
+    "dcos_variant":               EnumParam("open", "ee"),
+    "dcos_security":              EnumParam("strict", "permissive", "disabled"),
+  }
+}
+
+type PluginDcosGcpCmdAddCluster struct {
+  parent *PluginDcosGcp
+}
+
+func (p *PluginDcosGcpCmdAddCluster) GetName() string {
+  return "add-gcp-cluster"
+}
+
+func (p *PluginDcosGcpCmdAddCluster) GetDescription() string {
+  return "Adds a configuration file to deploy a DC/OS cluster on GCP"
+}
+
+func (p *PluginDcosGcpCmdAddCluster) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  var tfc TerraformFileConfig
+
+  config := project.GetConfig()
+
+  tfc.Flags = flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  fPassword := tfc.Flags.String("dcos_superuser_password", "", "The plain-text password to encode")
+  fOwner := tfc.Flags.String("owner", config.GetOwner(), "The user-name that owns this cluster")
+  fExpire := tfc.Flags.String("expiration", config.GetExpiration(), "How long to keep the cluster running before cloud-cleaner tears it down")
+  fName := tfc.Flags.String("name", "", "A unique id of the cluster, to deploy multiple clusters in the same project")
+  fProject := tfc.Flags.String("project", GetGCPProject(), "The GCP project to deploy the cluster in")
+  fRegion := tfc.Flags.String("region", "us-west1", "The GCP region to deploy the cluster in")
+
+  tfc.IgnoreFlags = []string{"owner", "expiration", "dcos_superuser_password", "project", "region", "name"}
+  tfc.Params = gcpClusterParams()
+
+  // The rest of the flags are the variables of the module version to use.
+  // The help page does not go to the network, and shows the variables of the
+  // local module or of the snapshot.
+  loadVariables := LoadModuleVariables
+  latestVersion := func() string {
+    return GetLatestProviderModuleVersion("gcp", "0.2.0")
+  }
+  if IsHelpRequested(args) {
+    loadVariables = LoadLocalModuleVariables
+    latestVersion = func() string {
+      return "0.2.0"
+    }
+  }
+  moduleVersion := config.GetModuleVersion("gcp", latestVersion)
+  moduleVars, err := loadVariables(project, "gcp", moduleVersion)
+  if err != nil {
+    return err
+  }
+  tfc.AddModuleVariables(moduleVars)
+
+  help := tfc.Flags.Bool("help", false, "Show this help message")
+  tfc.Flags.BoolVar(help, "h", false, "Show this help message")
+  err = tfc.Flags.Parse(args)
+  if err != nil {
+    FatalError(err)
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), "", []interface{}{
+      "This command will generate a 'cluster-gcp.tf' file in the project directory\n",
+      "that describes a deployment of a DC/OS cluster on GCP. A file with sane defaults",
+      "is created for you. You can override the values with the following flags:",
+    }, &tfc)
+    return nil
+  }
+
+  if *fName != "" {
+    if err := validateClusterId(*fName); err != nil {
+      return err
+    }
+  }
+  names := getClusterNames("gcp", *fName)
+  fileName := names.File
+  tfc.Block = []string{"module", names.Module}
+
+  // Hash password if given as hash input
+  if *fPassword != "" {
+    hash, err := hashSuperuserPassword(*fPassword)
+    if err != nil {
+      return err
+    }
+    tfc.Flags.Set("dcos_superuser_password_hash", hash)
+  }
+
+  projectLine := fmt.Sprintf(`  project = "%s"`, *fProject)
+  if *fProject == "" {
+    projectLine = `  # project = "my-gcp-project"`
+  }
+  tfc.PreLines = names.providerLines("google",
+    `  # Change your default project and region here`,
+    projectLine,
+    fmt.Sprintf(`  region  = "%s"`, *fRegion),
+  )
+  tfc.PreLines = append(tfc.PreLines, []string{
+    ``,
+    `# Used to determine your public IP for forwarding rules`,
+    fmt.Sprintf(`data "http" "%s" {`, names.WhatIsMyIP),
+    `  url = "http://whatismyip.akamai.com/"`,
+    `}`,
+    ``,
+    fmt.Sprintf(`module "%s" {`, names.Module),
+    `  source  = "dcos-terraform/dcos/gcp"`,
+    fmt.Sprintf(`  version = "~> %s"`, moduleVersion),
+    ``,
+    `  providers = {`,
+    fmt.Sprintf(`    google = "%s"`, names.providerRef("google")),
+    `  }`,
+    ``,
+  }...)
+  tfc.BodyLines = []string{
+    fmt.Sprintf(`  cluster_name               = "%s"`, names.clusterName()),
+    `  cluster_name_random_string = true`,
+    fmt.Sprintf(`  ssh_public_key_file        = "%s"`, names.SSHKey),
+    fmt.Sprintf(`  admin_ips                  = ["${data.http.%s.body}/32"]`, names.WhatIsMyIP),
+    ``,
+    `  num_masters        = 1`,
+    `  num_private_agents = 1`,
+    `  num_public_agents  = 1`,
+    ``,
+    fmt.Sprintf(`  dcos_version = "%s"`, GetLatestDCOSVersion("open", "2.0.0")),
+    ``,
+    `  ## If you have a DC/OS enterprise license, comment-out the following`,
+    `  ## lines and create a file "license.txt" in your project directory `,
+    `  ## containing the contents of your DC/OS license:`,
+    ``,
+    `  # dcos_variant              = "ee"`,
+    `  # dcos_security             = "permissive"`,
+    `  # dcos_license_key_contents = "${file("./license.txt")}"`,
+    ``,
+    `  dcos_variant = "open"`,
+    ``,
+    `  ## (Optionally) Use different superuser credentials`,
+    `  # dcos_superuser_username      = "superuser-name"`,
+    `  # dcos_superuser_password_hash = "${file("./dcos_superuser_password_hash.sha512")}"`,
+    ``,
+    `  dcos_instance_os            = "centos_7.6"`,
+    `  bootstrap_machine_type      = "n1-standard-2"`,
+    `  masters_machine_type        = "n1-standard-4"`,
+    `  private_agents_machine_type = "n1-standard-4"`,
+    `  public_agents_machine_type  = "n1-standard-4"`,
+  }
+
+  // GCP uses labels instead of tags, which only allow lowercase letters,
+  // digits, dashes and underscores
+  tfc.PostLines = []string{
+    ``,
+    `  labels = {`,
+    fmt.Sprintf(`    "expiration" = "%s"`, gcpLabelValue(*fExpire)),
+    fmt.Sprintf(`    "owner"      = "%s"`, gcpLabelValue(*fOwner)),
+  }
+  for _, k := range sortedStringKeys(config.Tags) {
+    tfc.PostLines = append(tfc.PostLines, fmt.Sprintf(`    "%s" = "%s"`, gcpLabelValue(k), gcpLabelValue(config.Tags[k])))
+  }
+  tfc.PostLines = append(tfc.PostLines, `  }`, `}`)
+  tfc.PostLines = append(tfc.PostLines, names.outputLines()...)
+
+  contents, err := tfc.Generate()
+  if err != nil {
+    return err
+  }
+
+  PrintInfo("%s%s%s", Bold("Writing "), Bold(Green(fileName)), Bold(" containing information for deploying a DC/OS cluster on GCP"))
+  p.parent.showInstructions = true
+  p.parent.createdFile = fileName
+
+  return project.WriteFormattedTerraformFile(fileName, contents)
+}
+
+var gcpLabelInvalidRe = regexp.MustCompile(`[^a-z0-9_-]`)
+
+/**
+ * Converts the given text to a valid GCP label key or value
+ */
+func gcpLabelValue(value string) string {
+  value = gcpLabelInvalidRe.ReplaceAllString(strings.ToLower(value), "_")
+  if len(value) > 63 {
+    value = value[:63]
+  }
+  return value
+}
@@ -20,6 +20,14 @@ func (p *PluginAddService) GetName() string {
   return "add-service"
 }
 
+func (p *PluginAddService) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginAddService) GetPriority() int {
+  return 0
+}
+
 func (p *PluginAddService) IsUsed(project *ProjectSandbox) (bool, error) {
   return false, nil
 }
@@ -19,6 +19,15 @@ func (p *PluginDcosProvider) GetName() string {
   return "dcos-provider"
 }
 
+func (p *PluginDcosProvider) GetDependencies() []string {
+  // The cluster module must be known before we can point the provider to it
+  return []string{"dcos-aws", "dcos-azure", "dcos-gcp"}
+}
+
+func (p *PluginDcosProvider) GetPriority() int {
+  return 0
+}
+
 func (p *PluginDcosProvider) IsUsed(project *ProjectSandbox) (bool, error) {
   dcos_data := project.GetTerraformResourcesMatchingName("data", "dcos_*")
   dcos_resource := project.GetTerraformResourcesMatchingName("resource", "dcos_*")
@@ -26,6 +35,7 @@ func (p *PluginDcosProvider) IsUsed(project *ProjectSandbox) (bool, error) {
 }
 
 func (p *PluginDcosProvider) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  mods := getDcosClusterModules(project)
 
   // If we are missing a DC/OS provider file, create it now
   provider := project.GetTerraformResourcesMatchingName("provider", "dcos")
@@ -40,6 +50,28 @@ func (p *PluginDcosProvider) BeforeRun(project *ProjectSandbox, tf *TerraformWra
     }
 
     PrintInfo("You are using dcos_ resources but you don't have a DC/OS provider. I created %s for you, please have a look", Bold(filename))
+    return nil
+  }
+
+  // With more than one cluster, every cluster needs its own aliased provider
+  if len(mods) > 1 {
+    aliases := project.GetProviderAliases("dcos")
+    for _, mod := range mods {
+      modName := mod["_name"].(string)
+      if containsString(aliases, modName) {
+        continue
+      }
+
+      content := []byte(strings.Join(p.getProviderBlock(mod, modName), "\n"))
+      filename := fmt.Sprintf("provider-dcos-%s.tf", modName)
+
+      err := project.WriteFormattedTerraformFile(filename, content)
+      if err != nil {
+        return err
+      }
+
+      PrintInfo("Created the DC/OS provider of the cluster %s in %s. Use `provider = \"dcos.%s\"` in the dcos_ resources of this cluster", Bold(modName), Bold(filename), modName)
+    }
   }
 
   return nil
@@ -56,26 +88,45 @@ func (p *PluginDcosProvider) GetCommands() []PluginCommand {
 func (p *PluginDcosProvider) getProviderContents(project *ProjectSandbox) []string {
   var cfg []string = []string{
     `// This connects to DC/OS and provides the dcos_* resources`,
-    `provider "dcos" {`,
   }
 
   // Check if we also have a launch module
-  mods := project.GetTerraformResourcesMatching("module", "source", "*dcos-terraform/dcos/aws")
+  mods := getDcosClusterModules(project)
   if len(mods) == 0 {
     // No launch module, we only rely on CLI
-    cfg = append(cfg, "}")
-    return cfg
+    return append(cfg, `provider "dcos" {`, "}")
+  }
+
+  // The default provider connects to the first cluster
+  cfg = append(cfg, p.getProviderBlock(mods[0], "")...)
+
+  // ...and every cluster has an aliased provider if there are more
+  if len(mods) > 1 {
+    for _, mod := range mods {
+      cfg = append(cfg, "")
+      cfg = append(cfg, p.getProviderBlock(mod, mod["_name"].(string))...)
+    }
+  }
+
+  return cfg
+}
+
+/**
+ * Returns a provider block that connects to the cluster of the given module
+ */
+func (p *PluginDcosProvider) getProviderBlock(clusterMod map[string]interface{}, alias string) []string {
+  cfg := []string{`provider "dcos" {`}
+  if alias != "" {
+    cfg = append(cfg, fmt.Sprintf(`  alias = "%s"`, alias))
   }
 
-  // Get the first deployment module
-  awsMod := mods[0]
-  awsModName := awsMod["_name"].(string)
-  cfg = append(cfg, fmt.Sprintf(`  dcos_url = "${module.%s.masters-loadbalancer}"`, awsModName))
+  clusterModName := clusterMod["_name"].(string)
+  cfg = append(cfg, fmt.Sprintf(`  dcos_url = "${module.%s.masters-loadbalancer}"`, clusterModName))
 
   // Get variant
   variant := "open"
-  if v, ok := awsMod["dcos_variant"]; ok {
-    variant = v.(string)
+  if v, ok := clusterMod["dcos_variant"].(string); ok {
+    variant = v
   }
 
   // If we have an ee variant, we can have password
@@ -0,0 +1,338 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+  "os"
+  "os/exec"
+  "path/filepath"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+const (
+  doctorPass = "pass"
+  doctorWarn = "warn"
+  doctorFail = "fail"
+)
+
+type doctorCheck struct {
+  Name    string
+  Status  string
+  Details string
+  Hint    string
+}
+
+type PluginDoctor struct {
+}
+
+func CreatePluginDoctor() *PluginDoctor {
+  return &PluginDoctor{}
+}
+
+func (p *PluginDoctor) GetName() string {
+  return "wheels-doctor"
+}
+
+func (p *PluginDoctor) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginDoctor) GetPriority() int {
+  return 0
+}
+
+func (p *PluginDoctor) IsUsed(project *ProjectSandbox) (bool, error) {
+  return false, nil
+}
+
+func (p *PluginDoctor) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  return nil
+}
+
+func (p *PluginDoctor) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  return nil
+}
+
+func (p *PluginDoctor) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginDoctorCmdDoctor{},
+  }
+}
+
+type PluginDoctorCmdDoctor struct {
+}
+
+func (p *PluginDoctorCmdDoctor) GetName() string {
+  return "wheels-doctor"
+}
+
+func (p *PluginDoctorCmdDoctor) GetDescription() string {
+  return "Checks your environment and project for common problems"
+}
+
+func (p *PluginDoctorCmdDoctor) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  fSet := flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  help := fSet.Bool("help", false, "Show this help message")
+  fSet.BoolVar(help, "h", false, "Show this help message")
+  err := fSet.Parse(args)
+  if err != nil {
+    return err
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), "", []interface{}{
+      "This command will check that terraform, ssh-agent and your cloud credentials",
+      "are usable, and that the project files are valid. It exits with a non-zero",
+      "code if any of the checks fails.",
+    }, fSet)
+    return nil
+  }
+
+  var checks []doctorCheck
+  checks = append(checks, p.checkConfig(project))
+  checks = append(checks, p.checkTerraform(project, tf))
+  checks = append(checks, p.checkTerraformFiles(project)...)
+  checks = append(checks, p.checkSSHAgent(project))
+  checks = append(checks, p.checkAWSCredentials(project))
+  checks = append(checks, p.checkAzureCredentials(project)...)
+  checks = append(checks, p.checkGCPCredentials(project)...)
+  checks = append(checks, p.checkSSHKeys(project)...)
+  checks = append(checks, p.checkDcosProvider(project))
+
+  failed := p.printChecks(checks)
+  if failed > 0 {
+    return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+  }
+  return nil
+}
+
+/**
+ * Prints the checks as a table followed by the hints of the ones that did
+ * not pass, and returns the number of failed checks
+ */
+func (p *PluginDoctorCmdDoctor) printChecks(checks []doctorCheck) int {
+  failed := 0
+  for _, check := range checks {
+    if check.Status == doctorFail {
+      failed++
+    }
+  }
+
+  if IsJSONOutput() {
+    for _, check := range checks {
+      level := "info"
+      if check.Status == doctorWarn {
+        level = "warning"
+      } else if check.Status == doctorFail {
+        level = "error"
+      }
+      PrintEvent(level, "doctor_check", map[string]interface{}{
+        "check":  check.Name,
+        "status": check.Status,
+        "hint":   check.Hint,
+      }, "%s", check.Details)
+    }
+    return failed
+  }
+
+  nameWidth := len("CHECK")
+  for _, check := range checks {
+    if len(check.Name) > nameWidth {
+      nameWidth = len(check.Name)
+    }
+  }
+
+  PrintMessage([]interface{}{fmt.Sprintf("%-6s %-*s %s", "STATUS", nameWidth, "CHECK", "DETAILS")})
+  for _, check := range checks {
+    var status interface{}
+    switch check.Status {
+    case doctorPass:
+      status = Green(fmt.Sprintf("%-6s", check.Status))
+    case doctorWarn:
+      status = Yellow(fmt.Sprintf("%-6s", check.Status))
+    default:
+      status = Red(fmt.Sprintf("%-6s", check.Status))
+    }
+    PrintMessage([]interface{}{
+      fmt.Sprintf("%s %-*s %s", status, nameWidth, check.Name, check.Details),
+    })
+  }
+
+  var hints []interface{}
+  for _, check := range checks {
+    if check.Status != doctorPass && check.Hint != "" {
+      hints = append(hints, fmt.Sprintf(" - %s: %s", Bold(check.Name), check.Hint))
+    }
+  }
+  if len(hints) > 0 {
+    PrintMessage(append([]interface{}{"", "How to fix:"}, hints...))
+  }
+
+  return failed
+}
+
+func (p *PluginDoctorCmdDoctor) checkConfig(project *ProjectSandbox) doctorCheck {
+  _, err := LoadWheelsConfig(project.GetBaseDir())
+  if err != nil {
+    return doctorCheck{"configuration", doctorFail, err.Error(),
+      fmt.Sprintf("Fix the syntax of %s or of your user configuration", ProjectConfigFile)}
+  }
+  return doctorCheck{"configuration", doctorPass, "Configuration is valid", ""}
+}
+
+func (p *PluginDoctorCmdDoctor) checkTerraform(project *ProjectSandbox, tf *TerraformWrapper) doctorCheck {
+  // Terraform is not downloaded for the doctor, so that it can report when
+  // it is missing
+  required := project.GetConfig().GetTerraformVersion()
+  if tf == nil {
+    tf = project.FindTerraform()
+  }
+  if tf == nil {
+    details := fmt.Sprintf("Terraform %sx was not found", required)
+    if path, err := exec.LookPath(ExecutableName("terraform")); err == nil {
+      if ver, err := CreateTeraformWrapper(path).GetVersion(); err == nil {
+        details = fmt.Sprintf("Found terraform v%s in your PATH, but %sx is required", ver, required)
+      }
+    }
+    return doctorCheck{"terraform", doctorFail, details,
+      fmt.Sprintf("Install terraform %sx in your PATH, or run any terraform command to download it in the project", required)}
+  }
+
+  ver, err := tf.GetVersion()
+  if err != nil {
+    return doctorCheck{"terraform", doctorFail, err.Error(),
+      "Delete the .terraform directory and run again to download terraform"}
+  }
+  return doctorCheck{"terraform", doctorPass, fmt.Sprintf("Using terraform v%s", ver), ""}
+}
+
+func (p *PluginDoctorCmdDoctor) checkTerraformFiles(project *ProjectSandbox) []doctorCheck {
+  files, err := project.ListTerraformFiles()
+  if err != nil {
+    return []doctorCheck{{"terraform files", doctorFail, err.Error(), "Check the permissions of the project directory"}}
+  }
+  if len(files) == 0 {
+    return []doctorCheck{{"terraform files", doctorWarn, "The project has no terraform files",
+      "Use `add-aws-cluster`, `add-azure-cluster`, `add-gcp-cluster` or `import-cluster` to create a cluster"}}
+  }
+
+  var checks []doctorCheck
+  for _, file := range files {
+    _, err := project.ReadTerraformFile(file)
+    if err != nil {
+      checks = append(checks, doctorCheck{file, doctorFail, err.Error(), "Fix the syntax error in the file"})
+    } else {
+      checks = append(checks, doctorCheck{file, doctorPass, "Parsed successfully", ""})
+    }
+  }
+  return checks
+}
+
+func (p *PluginDoctorCmdDoctor) checkSSHAgent(project *ProjectSandbox) doctorCheck {
+  _, err := CreateSSHAgentWrapper()
+  if err == nil {
+    return doctorCheck{"ssh-agent", doctorPass, "Found ssh-agent and ssh-add", ""}
+  }
+
+  // It's only a problem if the ssh-agent plugin is going to be used
+  status := doctorWarn
+  if used, _ := CreatePluginSSHAgent().IsUsed(project); used {
+    status = doctorFail
+  }
+  return doctorCheck{"ssh-agent", status, err.Error(),
+    "Install the OpenSSH client tools (ssh-agent and ssh-add)"}
+}
+
+func (p *PluginDoctorCmdDoctor) checkAWSCredentials(project *ProjectSandbox) doctorCheck {
+  if IsAWSCredsOK() {
+    return doctorCheck{"aws credentials", doctorPass, "AWS credentials are valid", ""}
+  }
+
+  // It's only a problem if the project uses AWS
+  status := doctorWarn
+  if used, _ := CreatePluginDcosAws().IsUsed(project); used {
+    status = doctorFail
+  }
+  return doctorCheck{"aws credentials", status, "Could not validate the AWS credentials",
+    "Configure your credentials with `aws configure` or `maws login`, or export AWS_PROFILE"}
+}
+
+func (p *PluginDoctorCmdDoctor) checkAzureCredentials(project *ProjectSandbox) []doctorCheck {
+  if IsAzureCredsOK() {
+    return []doctorCheck{{"azure credentials", doctorPass, "Found Azure credentials", ""}}
+  }
+
+  // Only report missing credentials if the project uses Azure
+  if used, _ := CreatePluginDcosAzure().IsUsed(project); !used {
+    return nil
+  }
+  return []doctorCheck{{"azure credentials", doctorFail, "Could not find the Azure credentials",
+    "Use `az login`, or export the ARM_* variables of a service principal"}}
+}
+
+func (p *PluginDoctorCmdDoctor) checkGCPCredentials(project *ProjectSandbox) []doctorCheck {
+  if IsGCPCredsOK() {
+    return []doctorCheck{{"gcp credentials", doctorPass, "Found GCP credentials", ""}}
+  }
+
+  // Only report missing credentials if the project uses GCP
+  if used, _ := CreatePluginDcosGcp().IsUsed(project); !used {
+    return nil
+  }
+  return []doctorCheck{{"gcp credentials", doctorFail, "Could not find the GCP credentials",
+    "Use `gcloud auth application-default login`, or export GOOGLE_APPLICATION_CREDENTIALS"}}
+}
+
+func (p *PluginDoctorCmdDoctor) checkSSHKeys(project *ProjectSandbox) []doctorCheck {
+  var checks []doctorCheck
+  mods := getDcosClusterModules(project)
+  for _, mod := range mods {
+    pubKey, ok := mod["ssh_public_key_file"].(string)
+    if !ok {
+      continue
+    }
+
+    name := fmt.Sprintf("ssh key %s", pubKey)
+    privKey := GetPrivateKeyNameFromPublic(pubKey)
+
+    // Missing keys in the project directory are created on the next run
+    if project.IsFileInSandbox(pubKey) && !project.HasFile(pubKey) {
+      checks = append(checks, doctorCheck{name, doctorWarn, "The key pair does not exist yet",
+        "It will be created the next time you run terraform"})
+      continue
+    }
+
+    if _, err := os.Stat(pubKey); err != nil {
+      checks = append(checks, doctorCheck{name, doctorFail,
+        fmt.Sprintf("Could not find the public key %s", pubKey),
+        "Fix the `ssh_public_key_file` path of the cluster module"})
+      continue
+    }
+    if _, err := os.Stat(privKey); err != nil {
+      checks = append(checks, doctorCheck{name, doctorFail,
+        fmt.Sprintf("Could not find the private key %s", privKey),
+        fmt.Sprintf("Place the private key next to the public key, as %s", filepath.Base(privKey))})
+      continue
+    }
+
+    checks = append(checks, doctorCheck{name, doctorPass, "Found the public and the private key", ""})
+  }
+
+  return checks
+}
+
+func (p *PluginDoctorCmdDoctor) checkDcosProvider(project *ProjectSandbox) doctorCheck {
+  used, _ := CreatePluginDcosProvider().IsUsed(project)
+  if !used {
+    return doctorCheck{"dcos provider", doctorPass, "No dcos_ resources are used", ""}
+  }
+
+  provider := project.GetTerraformResourcesMatchingName("provider", "dcos")
+  if len(provider) == 0 {
+    return doctorCheck{"dcos provider", doctorWarn, "There are dcos_ resources but no DC/OS provider",
+      "It will be created the next time you run terraform"}
+  }
+  return doctorCheck{"dcos provider", doctorPass, "The DC/OS provider is configured", ""}
+}
@@ -0,0 +1,354 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+  "sort"
+  "strings"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+  "gopkg.in/yaml.v3"
+)
+
+// The dcos-launch platforms of the cluster module providers
+var exportPlatforms map[string]string = map[string]string{
+  "aws":     "aws",
+  "azurerm": "azure",
+  "gcp":     "gcp",
+}
+
+// The node roles of the cluster modules, in the order their sizes are used,
+// and the size parameters that dcos-launch has for all the nodes
+var exportNodeRoles []string = []string{"private_agents", "masters", "public_agents"}
+var exportNodeParams []string = []string{"instance_type", "machine_type", "vm_size", "disk_size", "disk_type"}
+
+// The attributes of the cluster modules that are not exported, since they are
+// generated by `import-cluster` or have no meaning for dcos-launch
+var exportIgnoredAttributes []string = []string{
+  "_name", "_file", "source", "version", "providers",
+  "cluster_name_random_string", "public_agents_extra_volumes",
+}
+
+type PluginExportLaunchConfig struct {
+}
+
+func CreatePluginExportLaunchConfig() *PluginExportLaunchConfig {
+  return &PluginExportLaunchConfig{}
+}
+
+func (p *PluginExportLaunchConfig) GetName() string {
+  return "export-launch-config"
+}
+
+func (p *PluginExportLaunchConfig) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginExportLaunchConfig) GetPriority() int {
+  return 0
+}
+
+func (p *PluginExportLaunchConfig) IsUsed(project *ProjectSandbox) (bool, error) {
+  return false, nil
+}
+
+func (p *PluginExportLaunchConfig) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  return nil
+}
+
+func (p *PluginExportLaunchConfig) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  return nil
+}
+
+func (p *PluginExportLaunchConfig) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginExportLaunchConfigCmdExport{},
+  }
+}
+
+type PluginExportLaunchConfigCmdExport struct {
+}
+
+func (p *PluginExportLaunchConfigCmdExport) GetName() string {
+  return "export-launch-config"
+}
+
+func (p *PluginExportLaunchConfigCmdExport) GetDescription() string {
+  return "Exports the cluster of the project as dcos-launch configuration YAML"
+}
+
+func (p *PluginExportLaunchConfigCmdExport) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  fSet := flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  fModule := fSet.String("module", "", "The name of the cluster module to export, if the project has more than one")
+  fOutput := fSet.String("o", "cluster.yaml", "The file to write the configuration to, or - for the standard output")
+  fVarFile := fSet.String("var-file", "", "The tfvars file with the values of the variables (default terraform.tfvars)")
+  help := fSet.Bool("help", false, "Show this help message")
+  fSet.BoolVar(help, "h", false, "Show this help message")
+  err := fSet.Parse(args)
+  if err != nil {
+    return err
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), "", []interface{}{
+      "This command will convert the cluster module of the project into a",
+      "dcos-launch configuration YAML (provider: onprem), that can be imported back",
+      "with `import-cluster`. The parameters that dcos-launch does not support are",
+      "reported and left out.",
+    }, fSet)
+    return nil
+  }
+
+  mod, err := findClusterModule(project, *fModule)
+  if err != nil {
+    return err
+  }
+  if getClusterModuleProvider(mod) == "" {
+    return fmt.Errorf("Only the dcos-terraform cluster modules can be exported, %s uses %s", mod["_name"], mod["source"])
+  }
+
+  variables, err := readVariableValues(project, *fVarFile)
+  if err != nil {
+    return err
+  }
+
+  cfg, err := exportLaunchConfig(project, mod, variables)
+  if err != nil {
+    return err
+  }
+
+  contents, err := yaml.Marshal(cfg)
+  if err != nil {
+    return fmt.Errorf("Could not encode the configuration: %s", err.Error())
+  }
+
+  if *fOutput == "-" {
+    fmt.Print(string(contents))
+    return nil
+  }
+
+  PrintInfo("%s%s%s", Bold("Writing "), Bold(Green(*fOutput)), Bold(fmt.Sprintf(" containing the dcos-launch configuration of %s", mod["_name"])))
+  return project.WriteFile(*fOutput, contents)
+}
+
+/**
+ * Converts the attributes of a cluster module to a dcos-launch configuration.
+ * This is the reverse of what `import-cluster` does, so the `dcos_*` variables
+ * are folded back into the `dcos_config`.
+ */
+func exportLaunchConfig(project *ProjectSandbox, mod map[string]interface{}, variables map[string]interface{}) (*DcosLaunchInputConfig, error) {
+  cfg := &DcosLaunchInputConfig{
+    LaunchConfigVersion: 1,
+    Provider:            "onprem",
+    Platform:            exportPlatforms[getClusterModuleProvider(mod)],
+    DcosConfig:          make(map[string]interface{}),
+  }
+  if cfg.Platform == "aws" {
+    cfg.AwsRegion = getModuleRegion(project, mod, variables)
+  }
+
+  var names []string
+  for name := range mod {
+    if !containsString(exportIgnoredAttributes, name) {
+      names = append(names, name)
+    }
+  }
+  sort.Strings(names)
+
+  // The sizes of the nodes, per parameter and role
+  nodeSizes := make(map[string]map[string]interface{})
+  var errs []string
+  for _, name := range names {
+    value := plainHCLValue(resolveVariable(mod[name], variables))
+    str, _ := value.(string)
+
+    // The public IP of the user is what `import-cluster` uses by default
+    if name == "admin_ips" && strings.Contains(fmt.Sprintf("%v", value), "${data.http.") {
+      continue
+    }
+    if strings.Contains(fmt.Sprintf("%v", value), "${") {
+      PrintWarning("Not exporting %s: Interpolations cannot be exported", Bold(name))
+      continue
+    }
+
+    if param := exportNodeParam(name); param != "" {
+      if nodeSizes[param] == nil {
+        nodeSizes[param] = make(map[string]interface{})
+      }
+      nodeSizes[param][name] = value
+      continue
+    }
+
+    var err error
+    switch name {
+    case "cluster_name":
+      cfg.DeploymentName = str
+    case "num_masters":
+      cfg.NumMasters, err = hclInt(value)
+    case "num_private_agents":
+      cfg.NumPrivateAgents, err = hclInt(value)
+    case "num_public_agents":
+      cfg.NumPublicAgents, err = hclInt(value)
+    case "image":
+      cfg.SourceImage = str
+      if idx := strings.LastIndex(str, "/"); idx > 0 {
+        cfg.ImageProject, cfg.SourceImage = str[:idx], str[idx+1:]
+      }
+    case "location":
+      cfg.AzureLocation = str
+    case "ansible_user", "admin_username":
+      cfg.SshUser = str
+    case "aws_ami":
+      cfg.OsName = str
+    case "custom_dcos_download_path":
+      cfg.DcosInstallerUrl = str
+    case "dcos_version":
+      cfg.DcosVersion = str
+    case "dcos_config":
+      raw := make(map[string]interface{})
+      err = yaml.Unmarshal([]byte(str), &raw)
+      for k, v := range raw {
+        cfg.DcosConfig[k] = v
+      }
+    case "tags", "labels":
+      // The GCP module calls them labels
+      cfg.Tags, _ = value.(map[string]interface{})
+    case "aws_key_name":
+      cfg.AwsKeyName = str
+    case "ssh_public_key_file":
+      if str == "" {
+        // An existing EC2 key pair is used instead
+        continue
+      }
+      cfg.SshPrivateKeyFilename = GetPrivateKeyNameFromPublic(str)
+      if !project.HasFile(cfg.SshPrivateKeyFilename) {
+        PrintWarning("The private key %s was not found, make sure it exists next to the configuration", cfg.SshPrivateKeyFilename)
+      }
+    case "private_agents_extra_volumes":
+      cfg.AwsBlockDeviceMappings = exportBlockDeviceMappings(hclMaps(resolveVariable(mod[name], variables)))
+    default:
+      if strings.HasPrefix(name, "dcos_") {
+        cfg.DcosConfig[strings.TrimPrefix(name, "dcos_")] = value
+      } else {
+        PrintWarning("Not exporting %s: There is no dcos-launch equivalent", Bold(name))
+      }
+    }
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not export %s: %s", name, err.Error()))
+    }
+  }
+
+  // dcos-launch uses the same size for all the nodes
+  for _, param := range exportNodeParams {
+    if nodeSizes[param] == nil {
+      continue
+    }
+
+    var size interface{}
+    for _, role := range exportNodeRoles {
+      name := role + "_" + param
+      value, ok := nodeSizes[param][name]
+      if !ok {
+        continue
+      }
+      if size != nil && fmt.Sprintf("%v", size) != fmt.Sprintf("%v", value) {
+        PrintWarning("Not exporting %s: dcos-launch uses the same %s (%v) for all the nodes", Bold(name), param, size)
+        continue
+      }
+      size = value
+    }
+
+    var err error
+    switch param {
+    case "instance_type":
+      cfg.InstanceType, _ = size.(string)
+    case "machine_type":
+      cfg.MachineType, _ = size.(string)
+    case "vm_size":
+      cfg.VmSize, _ = size.(string)
+    case "disk_type":
+      cfg.DiskType, _ = size.(string)
+    case "disk_size":
+      cfg.DiskSize, err = hclInt(size)
+    }
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not export %s: %s", param, err.Error()))
+    }
+  }
+  if len(errs) > 0 {
+    return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+  }
+
+  if cfg.SshPrivateKeyFilename == "" && cfg.AwsKeyName == "" {
+    cfg.KeyHelper = true
+  }
+  if len(cfg.DcosConfig) == 0 {
+    cfg.DcosConfig = nil
+  }
+
+  return cfg, nil
+}
+
+/**
+ * Returns the size parameter of a node attribute (ex. `instance_type` for
+ * `masters_instance_type`), or an empty string if it's not one
+ */
+func exportNodeParam(name string) string {
+  for _, role := range exportNodeRoles {
+    param := strings.TrimPrefix(name, role+"_")
+    if param != name && containsString(exportNodeParams, param) {
+      return param
+    }
+  }
+  return ""
+}
+
+/**
+ * Converts the extra volumes of the AWS module to the block device mappings
+ * of boto, the reverse of `impotExtraVolumes`
+ */
+func exportBlockDeviceMappings(volumes []map[string]interface{}) []map[string]interface{} {
+  var mappings []map[string]interface{}
+  for _, volume := range volumes {
+    ebs := make(map[string]interface{})
+    if size, err := hclInt(volume["size"]); err == nil {
+      ebs["VolumeSize"] = size
+    }
+    if iops, err := hclInt(volume["iops"]); err == nil {
+      ebs["Iops"] = iops
+    }
+    if volumeType, ok := volume["type"].(string); ok {
+      ebs["VolumeType"] = volumeType
+    }
+    mappings = append(mappings, map[string]interface{}{
+      "DeviceName": volume["device_name"],
+      "Ebs":        ebs,
+    })
+  }
+  return mappings
+}
+
+/**
+ * Converts a decoded HCL value to plain maps and lists, since HCL decodes
+ * every map as a list of maps
+ */
+func plainHCLValue(value interface{}) interface{} {
+  switch tv := value.(type) {
+  case []map[string]interface{}:
+    ret := make(map[string]interface{})
+    for _, m := range tv {
+      for k, v := range m {
+        ret[k] = plainHCLValue(v)
+      }
+    }
+    return ret
+  case []interface{}:
+    var ret []interface{}
+    for _, item := range tv {
+      ret = append(ret, plainHCLValue(item))
+    }
+    return ret
+  }
+  return value
+}
@@ -0,0 +1,138 @@
+package plugins
+
+import (
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "reflect"
+  "testing"
+
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+  "gopkg.in/yaml.v3"
+)
+
+/**
+ * Creates an empty project that does not go to the network: the module
+ * versions are configured, and the module cache has no variables so the
+ * snapshot compiled in terraform-wheels is used
+ */
+func createTestProject(t *testing.T) (*ProjectSandbox, func()) {
+  dir, err := ioutil.TempDir("", "terraform-wheels-test")
+  if err != nil {
+    t.Fatalf("Could not create the project: %s", err.Error())
+  }
+
+  home := os.Getenv("HOME")
+  os.Setenv("HOME", filepath.Join(dir, ".home"))
+  cleanup := func() {
+    os.Setenv("HOME", home)
+    os.RemoveAll(dir)
+  }
+
+  config := "module_versions:\n  aws: 0.2.0\n  gcp: 0.2.0\n  azurerm: 0.2.0\n"
+  err = ioutil.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(config), 0644)
+  if err != nil {
+    cleanup()
+    t.Fatalf("Could not write the configuration: %s", err.Error())
+  }
+  for _, provider := range []string{"aws", "gcp", "azurerm"} {
+    cacheDir, _ := GetModuleCacheDir(provider, "0.2.0")
+    os.MkdirAll(cacheDir, os.ModePerm)
+    ioutil.WriteFile(filepath.Join(cacheDir, "variables.tf"), []byte{}, 0644)
+  }
+
+  project, err := OpenSandbox(dir)
+  if err != nil {
+    cleanup()
+    t.Fatalf("Could not open the project: %s", err.Error())
+  }
+  return project, cleanup
+}
+
+/**
+ * Imports a dcos-launch configuration in a new project with `import-cluster`
+ * and exports the cluster module back with `export-launch-config`
+ */
+func roundTripLaunchConfig(t *testing.T, file string, importArgs []string, exportArgs []string) []byte {
+  project, cleanup := createTestProject(t)
+  defer cleanup()
+
+  importCmd := &PluginImportClusterCmdImport{}
+  err := importCmd.Handle(append(importArgs, file), project, nil)
+  if err != nil {
+    t.Fatalf("Could not import %s: %s", file, err.Error())
+  }
+
+  err = project.ReloadTerraformProject()
+  if err != nil {
+    t.Fatalf("Could not parse the imported module of %s: %s", file, err.Error())
+  }
+
+  exportCmd := &PluginExportLaunchConfigCmdExport{}
+  err = exportCmd.Handle(append(exportArgs, "-o", "exported.yaml"), project, nil)
+  if err != nil {
+    t.Fatalf("Could not export %s: %s", file, err.Error())
+  }
+
+  contents, err := project.ReadFile("exported.yaml")
+  if err != nil {
+    t.Fatalf("Could not read the exported configuration: %s", err.Error())
+  }
+  return contents
+}
+
+/**
+ * Returns the configuration as generic YAML values, so that configurations
+ * are compared by their values and not by their formatting
+ */
+func launchConfigValues(t *testing.T, contents []byte) map[string]interface{} {
+  var values map[string]interface{}
+  err := yaml.Unmarshal(contents, &values)
+  if err != nil {
+    t.Fatalf("Could not decode the configuration: %s", err.Error())
+  }
+  return values
+}
+
+func TestLaunchConfigRoundTrip(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string // The input itself if empty
+  }{
+    {"aws.yaml", ""},
+    {"gcp.yaml", ""},
+    {"azure.yaml", ""},
+    {"cloudformation.yaml", "cloudformation.expected.yaml"},
+    {"cloudformation-zen.yaml", "cloudformation-zen.expected.yaml"},
+    {"cloudformation-keyname.yaml", "cloudformation-keyname.expected.yaml"},
+  }
+
+  // The values given in variables must be exported the same way
+  modes := []struct {
+    name       string
+    importArgs []string
+    exportArgs []string
+  }{
+    {"module", nil, nil},
+    {"variables", []string{"-as-variables"}, nil},
+    {"environment", []string{"-as-variables", "-environment", "staging"}, []string{"-var-file", "staging.tfvars"}},
+  }
+
+  for _, test := range tests {
+    expectedFile := test.expected
+    if expectedFile == "" {
+      expectedFile = test.input
+    }
+    expected, err := ioutil.ReadFile(filepath.Join("testdata", "launch", expectedFile))
+    if err != nil {
+      t.Fatalf("Could not read %s: %s", expectedFile, err.Error())
+    }
+
+    for _, mode := range modes {
+      exported := roundTripLaunchConfig(t, filepath.Join("testdata", "launch", test.input), mode.importArgs, mode.exportArgs)
+      if !reflect.DeepEqual(launchConfigValues(t, exported), launchConfigValues(t, expected)) {
+        t.Errorf("%s (%s): expected:\n%s\ngot:\n%s", test.input, mode.name, string(expected), string(exported))
+      }
+    }
+  }
+}
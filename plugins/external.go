@@ -0,0 +1,278 @@
+package plugins
+
+import (
+  "encoding/json"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "runtime"
+  "strings"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+// The version of the JSON-over-stdio protocol spoken with external plugins
+const ExternalPluginProtocolVersion = 1
+
+/**
+ * The request sent to the plugin executable on stdin. Every call to the
+ * plugin is a separate process invocation with exactly one request.
+ */
+type ExternalPluginRequest struct {
+  Version int    `json:"version"`
+  Method  string `json:"method"`
+
+  ProjectDir       string                                       `json:"project_dir"`
+  ProjectResources map[string]map[string]map[string]interface{} `json:"project_resources"`
+
+  // BeforeRun
+  InitRun bool `json:"init_run,omitempty"`
+
+  // AfterRun
+  TerraformError string `json:"terraform_error,omitempty"`
+
+  // Handle
+  Command string   `json:"command,omitempty"`
+  Args    []string `json:"args,omitempty"`
+}
+
+type ExternalPluginMessage struct {
+  Level string `json:"level"`
+  Text  string `json:"text"`
+}
+
+type ExternalPluginCommandInfo struct {
+  Name        string `json:"name"`
+  Description string `json:"description"`
+}
+
+/**
+ * The response the plugin executable is expected to write on stdout. The
+ * plugin's stderr is passed through to the user.
+ */
+type ExternalPluginResponse struct {
+  // Describe
+  Name         string                      `json:"name"`
+  Commands     []ExternalPluginCommandInfo `json:"commands"`
+  Dependencies []string                    `json:"dependencies"`
+  Priority     int                         `json:"priority"`
+
+  // IsUsed
+  Used bool `json:"used"`
+
+  // Environment variables to inject into the terraform process
+  Env map[string]string `json:"env"`
+
+  Messages []ExternalPluginMessage `json:"messages"`
+  Error    string                  `json:"error"`
+}
+
+type PluginExternal struct {
+  path         string
+  name         string
+  commands     []PluginCommand
+  dependencies []string
+  priority     int
+}
+
+/**
+ * Returns the directories where external plugin executables are looked up
+ */
+func GetExternalPluginDirs(project *ProjectSandbox) []string {
+  var dirs []string
+  if home, err := os.UserHomeDir(); err == nil {
+    dirs = append(dirs, filepath.Join(home, ".terraform-wheels", "plugins"))
+  }
+  dirs = append(dirs, project.GetFilePath(filepath.Join(".wheels", "plugins")))
+  return dirs
+}
+
+/**
+ * Scans the plugin directories for executables and loads them as plugins
+ */
+func DiscoverExternalPlugins(project *ProjectSandbox) []Plugin {
+  var ret []Plugin = nil
+
+  for _, dir := range GetExternalPluginDirs(project) {
+    files, err := ioutil.ReadDir(dir)
+    if err != nil {
+      continue
+    }
+
+    for _, file := range files {
+      if file.IsDir() || !isExecutableFile(file) {
+        continue
+      }
+
+      plugin, err := CreatePluginExternal(filepath.Join(dir, file.Name()), project)
+      if err != nil {
+        PrintWarning("Ignoring external plugin %s: %s", Bold(file.Name()), err.Error())
+        continue
+      }
+
+      ret = append(ret, plugin)
+    }
+  }
+
+  return ret
+}
+
+func isExecutableFile(file os.FileInfo) bool {
+  if runtime.GOOS == "windows" {
+    return strings.HasSuffix(strings.ToLower(file.Name()), ".exe")
+  }
+  return file.Mode()&0111 != 0
+}
+
+func CreatePluginExternal(path string, project *ProjectSandbox) (*PluginExternal, error) {
+  p := &PluginExternal{path, filepath.Base(path), nil, nil, 0}
+
+  resp, err := p.call(project, ExternalPluginRequest{Method: "Describe"})
+  if err != nil {
+    return nil, err
+  }
+
+  if resp.Name != "" {
+    p.name = resp.Name
+  }
+  p.dependencies = resp.Dependencies
+  p.priority = resp.Priority
+  for _, cmd := range resp.Commands {
+    p.commands = append(p.commands, &PluginExternalCmd{p, cmd.Name, cmd.Description})
+  }
+
+  return p, nil
+}
+
+/**
+ * Invokes the plugin executable with the given request and parses its response
+ */
+func (p *PluginExternal) call(project *ProjectSandbox, req ExternalPluginRequest) (*ExternalPluginResponse, error) {
+  req.Version = ExternalPluginProtocolVersion
+  req.ProjectDir = project.GetBaseDir()
+  req.ProjectResources = project.GetTerraformProject()
+
+  input, err := json.Marshal(req)
+  if err != nil {
+    return nil, fmt.Errorf("Could not encode request: %s", err.Error())
+  }
+
+  env := []string{
+    fmt.Sprintf("WHEELS_PLUGIN_PROTOCOL=%d", ExternalPluginProtocolVersion),
+    fmt.Sprintf("WHEELS_PROJECT_DIR=%s", project.GetBaseDir()),
+  }
+  code, sout, err := ExecuteInFolderWithInput(project.GetBaseDir(), env, input, p.path)
+  if err != nil {
+    return nil, fmt.Errorf("Could not execute %s: %s", p.path, err.Error())
+  }
+
+  var resp ExternalPluginResponse
+  if strings.TrimSpace(sout) != "" {
+    err = json.Unmarshal([]byte(sout), &resp)
+    if err != nil {
+      return nil, fmt.Errorf("Invalid response to %s: %s", req.Method, err.Error())
+    }
+  }
+
+  for _, msg := range resp.Messages {
+    if msg.Level == "warning" {
+      PrintWarning("%s", msg.Text)
+    } else {
+      PrintInfo("%s", msg.Text)
+    }
+  }
+
+  if resp.Error != "" {
+    return nil, fmt.Errorf("%s", resp.Error)
+  }
+  if code != 0 {
+    return nil, fmt.Errorf("%s exited with code %d", req.Method, code)
+  }
+
+  return &resp, nil
+}
+
+/**
+ * Calls the plugin and forwards the environment variables it returned
+ */
+func (p *PluginExternal) callWithEnv(project *ProjectSandbox, tf *TerraformWrapper, req ExternalPluginRequest) (*ExternalPluginResponse, error) {
+  resp, err := p.call(project, req)
+  if err != nil {
+    return nil, err
+  }
+
+  if tf != nil {
+    for k, v := range resp.Env {
+      tf.SetEnv(k, v)
+    }
+  }
+
+  return resp, nil
+}
+
+func (p *PluginExternal) GetName() string {
+  return p.name
+}
+
+func (p *PluginExternal) GetDependencies() []string {
+  return p.dependencies
+}
+
+func (p *PluginExternal) GetPriority() int {
+  return p.priority
+}
+
+func (p *PluginExternal) IsUsed(project *ProjectSandbox) (bool, error) {
+  resp, err := p.call(project, ExternalPluginRequest{Method: "IsUsed"})
+  if err != nil {
+    return false, err
+  }
+  return resp.Used, nil
+}
+
+func (p *PluginExternal) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  _, err := p.callWithEnv(project, tf, ExternalPluginRequest{
+    Method:  "BeforeRun",
+    InitRun: initRun,
+  })
+  return err
+}
+
+func (p *PluginExternal) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  req := ExternalPluginRequest{Method: "AfterRun"}
+  if tfErr != nil {
+    req.TerraformError = tfErr.Error()
+  }
+
+  _, err := p.callWithEnv(project, tf, req)
+  return err
+}
+
+func (p *PluginExternal) GetCommands() []PluginCommand {
+  return p.commands
+}
+
+type PluginExternalCmd struct {
+  parent      *PluginExternal
+  name        string
+  description string
+}
+
+func (p *PluginExternalCmd) GetName() string {
+  return p.name
+}
+
+func (p *PluginExternalCmd) GetDescription() string {
+  return p.description
+}
+
+func (p *PluginExternalCmd) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  _, err := p.parent.callWithEnv(project, tf, ExternalPluginRequest{
+    Method:  "Handle",
+    Command: p.name,
+    Args:    args,
+  })
+  return err
+}
@@ -6,6 +6,11 @@ import (
   "fmt"
   "io/ioutil"
   "os"
+  "path/filepath"
+  "reflect"
+  "regexp"
+  "sort"
+  "strconv"
   "strings"
 
   . "github.com/logrusorgru/aurora"
@@ -15,48 +20,199 @@ import (
 
 type DcosLaunchInputConfig struct {
   // Universal Parameters
-  KeyHelper             bool                   `yaml:"key_helper"`
-  Provider              string                 `yaml:"provider"`
-  SshPort               int                    `yaml:"ssh_port"`
-  SshPrivateKey         string                 `yaml:"ssh_private_key"`
-  SshPrivateKeyFilename string                 `yaml:"ssh_private_key_filename"`
-  SshUser               string                 `yaml:"ssh_user"`
-  Tags                  map[string]interface{} `yaml:"tags"`
-  ZenHelper             bool                   `yaml:"zen_helper"`
+  LaunchConfigVersion   int                    `yaml:"launch_config_version,omitempty"`
+  KeyHelper             bool                   `yaml:"key_helper,omitempty"`
+  Provider              string                 `yaml:"provider,omitempty"`
+  SshPort               int                    `yaml:"ssh_port,omitempty"`
+  SshPrivateKey         string                 `yaml:"ssh_private_key,omitempty"`
+  SshPrivateKeyFilename string                 `yaml:"ssh_private_key_filename,omitempty"`
+  SshUser               string                 `yaml:"ssh_user,omitempty"`
+  Tags                  map[string]interface{} `yaml:"tags,omitempty"`
+  ZenHelper             bool                   `yaml:"zen_helper,omitempty"`
 
   // (Undocumented)
-  Enterprise bool `yaml:"dcos-enterprise"`
+  Enterprise bool `yaml:"dcos-enterprise,omitempty"`
 
   // Template-based deploy params
-  TemplateParameters map[string]interface{} `yaml:"template_parameters"`
-  TemplateUrl        string                 `yaml:"template_url"`
+  TemplateParameters map[string]interface{} `yaml:"template_parameters,omitempty"`
+  TemplateUrl        string                 `yaml:"template_url,omitempty"`
 
   // On-prem
-  DcosConfig               map[string]interface{} `yaml:"dcos_config"`
-  DcosVersion              string                 `yaml:"dcos_version"`
-  DcosInstallerUrl         string                 `yaml:"installer_url"`
-  DeploymentName           string                 `yaml:"deployment_name"`
-  FaultDomainHelper        string                 `yaml:"fault_domain_helper"`
-  GenconfDir               string                 `yaml:"genconf_dir"`
-  InstallPrereqs           bool                   `yaml:"install_prereqs"`
-  InstallerPort            int                    `yaml:"installer_port"`
-  NumMasters               int                    `yaml:"num_masters"`
-  NumPrivateAgents         int                    `yaml:"num_private_agents"`
-  NumPublicAgents          int                    `yaml:"num_public_agents"`
-  OnpremInstallParallelism int                    `yaml:"onprem_install_parallelism"`
-  Platform                 string                 `yaml:"platform"`
-  PrereqsScriptFilename    string                 `yaml:"prereqs_script_filename"`
+  DcosConfig               map[string]interface{} `yaml:"dcos_config,omitempty"`
+  DcosVersion              string                 `yaml:"dcos_version,omitempty"`
+  DcosInstallerUrl         string                 `yaml:"installer_url,omitempty"`
+  DeploymentName           string                 `yaml:"deployment_name,omitempty"`
+  FaultDomainHelper        string                 `yaml:"fault_domain_helper,omitempty"`
+  GenconfDir               string                 `yaml:"genconf_dir,omitempty"`
+  InstallPrereqs           bool                   `yaml:"install_prereqs,omitempty"`
+  InstallerPort            int                    `yaml:"installer_port,omitempty"`
+  NumMasters               int                    `yaml:"num_masters,omitempty"`
+  NumPrivateAgents         int                    `yaml:"num_private_agents,omitempty"`
+  NumPublicAgents          int                    `yaml:"num_public_agents,omitempty"`
+  OnpremInstallParallelism int                    `yaml:"onprem_install_parallelism,omitempty"`
+  Platform                 string                 `yaml:"platform,omitempty"`
+  PrereqsScriptFilename    string                 `yaml:"prereqs_script_filename,omitempty"`
 
   // AWS On-Prem
-  AwsRegion              string                   `yaml:"aws_region"`
-  AdminLocation          string                   `yaml:"admin_location"`
-  AwsKeyName             string                   `yaml:"aws_key_name"`
-  BootstrapSshUser       string                   `yaml:"bootstrap_ssh_user"`
-  InstanceDeviceName     string                   `yaml:"instance_device_name"`
-  InstanceType           string                   `yaml:"instance_type"`
-  OsName                 string                   `yaml:"os_name"`
-  AwsBlockDeviceMappings []map[string]interface{} `yaml:"aws_block_device_mappings"`
-  IamRolePermissions     []map[string]interface{} `yaml:"iam_role_permissions"`
+  AwsRegion              string                   `yaml:"aws_region,omitempty"`
+  AdminLocation          string                   `yaml:"admin_location,omitempty"`
+  AwsKeyName             string                   `yaml:"aws_key_name,omitempty"`
+  BootstrapSshUser       string                   `yaml:"bootstrap_ssh_user,omitempty"`
+  InstanceDeviceName     string                   `yaml:"instance_device_name,omitempty"`
+  InstanceType           string                   `yaml:"instance_type,omitempty"`
+  OsName                 string                   `yaml:"os_name,omitempty"`
+  AwsBlockDeviceMappings []map[string]interface{} `yaml:"aws_block_device_mappings,omitempty"`
+  IamRolePermissions     []map[string]interface{} `yaml:"iam_role_permissions,omitempty"`
+
+  // GCP On-Prem
+  GceZone           string `yaml:"gce_zone,omitempty"`
+  MachineType       string `yaml:"machine_type,omitempty"`
+  SourceImage       string `yaml:"source_image,omitempty"`
+  ImageProject      string `yaml:"image_project,omitempty"`
+  UsePreemptibleVMs bool   `yaml:"use_preemptible_vms,omitempty"`
+
+  // Azure On-Prem
+  AzureLocation string `yaml:"azure_location,omitempty"`
+  VmSize        string `yaml:"vm_size,omitempty"`
+
+  // GCP and Azure On-Prem
+  DiskSize int    `yaml:"disk_size,omitempty"`
+  DiskType string `yaml:"disk_type,omitempty"`
+}
+
+// The variables of the AWS module for the parameters of the CloudFormation
+// templates. The simple and the advanced templates use different names for
+// the same parameters.
+var cloudFormationParameters map[string]string = map[string]string{
+  "KeyName":                   "aws_key_name",
+  "SlaveInstanceCount":        "num_private_agents",
+  "PrivateAgentInstanceCount": "num_private_agents",
+  "PublicSlaveInstanceCount":  "num_public_agents",
+  "PublicAgentInstanceCount":  "num_public_agents",
+  "MasterInstanceType":        "masters_instance_type",
+  "PrivateAgentInstanceType":  "private_agents_instance_type",
+  "PublicAgentInstanceType":   "public_agents_instance_type",
+  "CustomAMI":                 "aws_ami",
+  "OAuthEnabled":              "dcos_oauth_enabled",
+  "LicenseKey":                "dcos_license_key_contents",
+}
+
+// Why some of the parameters of the CloudFormation templates are not imported
+var cloudFormationUnsupported map[string]string = map[string]string{
+  "Vpc":               "the AWS module creates its own VPC",
+  "InternetGateway":   "the AWS module creates its own VPC",
+  "PublicSubnet":      "the AWS module creates its own subnets",
+  "PrivateSubnet":     "the AWS module creates its own subnets",
+  "ExhibitorS3Bucket": "the masters are used as the exhibitor storage backend",
+}
+
+var cloudFormationVersionRe = regexp.MustCompile(`/([0-9]+\.[0-9]+(?:\.[0-9]+)?)/cloudformation/`)
+var cloudFormationMastersRe = regexp.MustCompile(`(single|multi)-master|-master-([0-9]+)|zen-([0-9]+)`)
+
+// The scripts of a genconf directory, and the DC/OS options that give their
+// contents and their file name
+var genconfScripts map[string]string = map[string]string{
+  "ip-detect":           "ip_detect",
+  "ip-detect-public":    "ip_detect_public",
+  "fault-domain-detect": "fault_domain_detect",
+}
+
+// The options of a genconf `config.yaml` that the module sets itself
+var genconfManagedOptions []string = []string{
+  "agent_list", "bootstrap_url", "master_list", "public_agent_list",
+}
+
+const (
+  importMapped       = "mapped"
+  importApproximated = "approximated"
+  importIgnored      = "ignored"
+  importUnknown      = "unknown"
+)
+
+/**
+ * How a key of the dcos-launch configuration was imported. Nested keys are
+ * given as a path, ex. `template_parameters.KeyName`
+ */
+type importReportEntry struct {
+  Key     string `yaml:"key"`
+  Status  string `yaml:"status"`
+  Target  string `yaml:"target,omitempty"`
+  Details string `yaml:"details,omitempty"`
+}
+
+type importReport struct {
+  Source  string              `yaml:"source"`
+  File    string              `yaml:"file"`
+  Entries []importReportEntry `yaml:"keys"`
+}
+
+func (r *importReport) add(key string, status string, target string, details string) {
+  r.Entries = append(r.Entries, importReportEntry{key, status, target, details})
+}
+
+/**
+ * Checks if the report has an entry for the given key, or for any of its
+ * nested keys
+ */
+func (r *importReport) has(key string) bool {
+  for _, entry := range r.Entries {
+    if entry.Key == key || strings.HasPrefix(entry.Key, key+".") || strings.HasPrefix(entry.Key, key+"[") {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Returns the entries that were not mapped exactly
+ */
+func (r *importReport) getInexact() []importReportEntry {
+  var entries []importReportEntry
+  for _, entry := range r.Entries {
+    if entry.Status != importMapped {
+      entries = append(entries, entry)
+    }
+  }
+  return entries
+}
+
+/**
+ * Adds the keys of the configuration that none of the importers handled,
+ * either because they have no terraform equivalent or because they are not
+ * dcos-launch parameters at all (and were dropped while parsing)
+ */
+func (r *importReport) addRemainingKeys(contents []byte, cfg *DcosLaunchInputConfig) error {
+  raw := make(map[string]interface{})
+  err := yaml.Unmarshal(contents, &raw)
+  if err != nil {
+    return fmt.Errorf("Could not parse the configuration: %s", err.Error())
+  }
+
+  fields := make(map[string]reflect.Value)
+  cfgValue := reflect.ValueOf(cfg).Elem()
+  for i := 0; i < cfgValue.NumField(); i++ {
+    name := strings.Split(cfgValue.Type().Field(i).Tag.Get("yaml"), ",")[0]
+    fields[name] = cfgValue.Field(i)
+  }
+
+  for _, key := range sortedInterfaceKeys(raw) {
+    if r.has(key) {
+      continue
+    }
+    field, ok := fields[key]
+    if !ok {
+      r.add(key, importUnknown, "", "not a dcos-launch parameter")
+    } else if field.IsZero() {
+      r.add(key, importMapped, "", "empty, the module default is used")
+    } else {
+      r.add(key, importIgnored, "", fmt.Sprintf("no terraform equivalent for the %s provider", cfg.Provider))
+    }
+  }
+
+  sort.SliceStable(r.Entries, func(i, j int) bool {
+    return r.Entries[i].Key < r.Entries[j].Key
+  })
+  return nil
 }
 
 type PluginImportCluster struct {
@@ -70,6 +226,14 @@ func (p *PluginImportCluster) GetName() string {
   return "import-cluster"
 }
 
+func (p *PluginImportCluster) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginImportCluster) GetPriority() int {
+  return 0
+}
+
 func (p *PluginImportCluster) IsUsed(project *ProjectSandbox) (bool, error) {
   return false, nil
 }
@@ -89,6 +253,12 @@ func (p *PluginImportCluster) GetCommands() []PluginCommand {
 }
 
 type PluginImportClusterCmdImport struct {
+  names          clusterNames
+  report         *importReport
+  writeKeys      func() error    // Writes the key pair, once the import is accepted
+  genconfFiles   []genconfFile   // The files of the genconf directory to copy
+  genconfOptions map[string]bool // The DC/OS options from the genconf `config.yaml`
+  configKeys     map[string]bool // The top-level keys given in the configuration
 }
 
 func (p *PluginImportClusterCmdImport) GetName() string {
@@ -100,19 +270,40 @@ func (p *PluginImportClusterCmdImport) GetDescription() string {
 }
 
 func (p *PluginImportClusterCmdImport) importSSHKeys(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
-  sshKey := "cluster-key.pub"
-  if cfg.DeploymentName != "" {
-    sshKey = fmt.Sprintf("cluster-%s-key.pub", cfg.DeploymentName)
-  }
+  sshKey := p.names.SSHKey
   fPublicKey := sshKey
   fPrivateKey := GetPrivateKeyNameFromPublic(sshKey)
 
+  // Only the first of the ways to give the key is used
+  var given []string
   if cfg.KeyHelper {
-    PrintInfo("Generating SSH key-pair because `key_helper` is used")
+    given = append(given, "key_helper")
+  }
+  if cfg.SshPrivateKey != "" {
+    given = append(given, "ssh_private_key")
+  }
+  if cfg.SshPrivateKeyFilename != "" {
+    given = append(given, "ssh_private_key_filename")
+  }
+  for i, key := range given {
+    if i == 0 {
+      p.report.add(key, importMapped, "ssh_public_key_file", "")
+    } else {
+      p.report.add(key, importIgnored, "", fmt.Sprintf("`%s` is used instead", given[0]))
+    }
+  }
 
-    err := CreateRSAKeyPair(fPrivateKey, fPublicKey)
-    if err != nil {
-      return nil, fmt.Errorf("Could not create RSA keypair: %s", err.Error())
+  // The keys are only written after the report is checked, so that an
+  // import that fails leaves nothing behind
+  if cfg.KeyHelper {
+    p.writeKeys = func() error {
+      PrintInfo("Generating SSH key-pair because `key_helper` is used")
+
+      err := project.WriteRSAKeyPair(fPrivateKey, fPublicKey)
+      if err != nil {
+        return fmt.Errorf("Could not create RSA keypair: %s", err.Error())
+      }
+      return nil
     }
 
     return []string{
@@ -121,18 +312,20 @@ func (p *PluginImportClusterCmdImport) importSSHKeys(cfg *DcosLaunchInputConfig,
       "",
     }, nil
   } else if cfg.SshPrivateKey != "" {
-    PrintInfo("Dumping private/public key pair from private key contents")
-
-    privateKeyBytes := []byte(cfg.SshPrivateKey)
+    p.writeKeys = func() error {
+      PrintInfo("Dumping private/public key pair from private key contents")
 
-    err := ioutil.WriteFile(fPrivateKey, privateKeyBytes, 0644)
-    if err != nil {
-      return nil, fmt.Errorf("Error writing private key %s: %s", fPrivateKey, err.Error())
-    }
+      privateKeyBytes := []byte(cfg.SshPrivateKey)
+      publicKeyBytes, err := GeneratePublicRSAKey(privateKeyBytes)
+      if err != nil {
+        return fmt.Errorf("Could not read the private key: %s", err.Error())
+      }
 
-    err = CreatePublicRSAKeyFromPrivate(privateKeyBytes, fPublicKey)
-    if err != nil {
-      return nil, fmt.Errorf("Error writing public key %s: %s", fPublicKey, err.Error())
+      err = project.WriteFileWithMode(fPrivateKey, privateKeyBytes, 0600)
+      if err != nil {
+        return err
+      }
+      return project.WriteFileWithMode(fPublicKey, publicKeyBytes, 0600)
     }
 
     return []string{
@@ -145,7 +338,7 @@ func (p *PluginImportClusterCmdImport) importSSHKeys(cfg *DcosLaunchInputConfig,
     fPublicKey = GetPublicKeyNameFromPrivate(cfg.SshPrivateKeyFilename)
     _, err := os.Stat(fPublicKey)
     if err != nil {
-      return nil, fmt.Errorf("Did not find the respective public key for %s (looking at %s)", fPublicKey)
+      return nil, fmt.Errorf("Did not find the respective public key for %s (looking at %s)", cfg.SshPrivateKeyFilename, fPublicKey)
     }
 
     return []string{
@@ -154,8 +347,6 @@ func (p *PluginImportClusterCmdImport) importSSHKeys(cfg *DcosLaunchInputConfig,
   } else {
     return nil, fmt.Errorf("Please use one of: `key_helper`, `ssh_private_key` or `ssh_private_key_filename`")
   }
-
-  return nil, nil
 }
 
 func (p *PluginImportClusterCmdImport) importDcosConfig(cfg map[string]interface{}, project *ProjectSandbox) ([]string, error) {
@@ -205,7 +396,9 @@ func (p *PluginImportClusterCmdImport) importDcosConfig(cfg map[string]interface
     "zk_agent_credentials", "zk_master_credentials", "zk_super_credentials",
   }
 
-  for k, iv := range cfg {
+  // Sort the keys, so that importing the same configuration gives the same file
+  for _, k := range sortedInterfaceKeys(cfg) {
+    iv := cfg[k]
     hasMapping := false
     for _, n := range mapVars {
       if n == k {
@@ -217,11 +410,13 @@ func (p *PluginImportClusterCmdImport) importDcosConfig(cfg map[string]interface
     if hasMapping {
       // If there is a direct mapping convert the X to dcos_x variable and
       // export it as proper terraform variable
+      p.report.add(p.getDcosConfigKey(k), importMapped, "dcos_"+k, "")
       k = "dcos_" + k
       switch v := iv.(type) {
       case map[string]interface{}:
         lines = append(lines, fmt.Sprintf("%s = {", k))
-        for ek, e := range v {
+        for _, ek := range sortedInterfaceKeys(v) {
+          e := v[ek]
           lines = append(lines, fmt.Sprintf("  %s = %s,", ek, FormatJSON(e)))
         }
         lines = append(lines, "}")
@@ -237,6 +432,7 @@ func (p *PluginImportClusterCmdImport) importDcosConfig(cfg map[string]interface
 
     } else {
       // If there is no mapping, append it to raw DCOS config vars
+      p.report.add(p.getDcosConfigKey(k), importMapped, "dcos_config", "")
       rawDcosConfig[k] = iv
     }
   }
@@ -259,13 +455,167 @@ func (p *PluginImportClusterCmdImport) importDcosConfig(cfg map[string]interface
   return lines, nil
 }
 
+/**
+ * Returns the key of a DC/OS option in the import report, depending on
+ * whether it was given in the `dcos_config` or in the genconf `config.yaml`
+ */
+func (p *PluginImportClusterCmdImport) getDcosConfigKey(option string) string {
+  if p.genconfOptions[option] {
+    return "genconf_dir/config.yaml." + option
+  }
+  return "dcos_config." + option
+}
+
+/**
+ * A file of the genconf directory and where it's copied in the project
+ */
+type genconfFile struct {
+  Source string
+  Target string
+  Mode   os.FileMode
+}
+
+/**
+ * Gives the scripts of the genconf directory of the configuration to the
+ * module with `file()` and merges its `config.yaml` into the `dcos_config`,
+ * which takes precedence. Returns the files to copy into the project, which
+ * are only copied once the import is accepted.
+ */
+func (p *PluginImportClusterCmdImport) importGenconf(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, []genconfFile, error) {
+  var lines []string = nil
+
+  dstDir := "genconf"
+  if p.names.Id != "" {
+    dstDir = fmt.Sprintf("genconf-%s", p.names.Id)
+  }
+  p.report.add("genconf_dir", importMapped, dstDir, "")
+
+  info, err := os.Stat(cfg.GenconfDir)
+  if err != nil {
+    return nil, nil, fmt.Errorf("Could not find the genconf directory: %s", err.Error())
+  }
+  if !info.IsDir() {
+    return nil, nil, fmt.Errorf("The `genconf_dir` %s is not a directory", cfg.GenconfDir)
+  }
+
+  var files []string
+  var copies []genconfFile
+  err = filepath.Walk(cfg.GenconfDir, func(path string, info os.FileInfo, err error) error {
+    if err != nil || info.IsDir() {
+      return err
+    }
+    rel, err := filepath.Rel(cfg.GenconfDir, path)
+    if err != nil {
+      return err
+    }
+    name := filepath.ToSlash(rel)
+    if !info.Mode().IsRegular() {
+      p.report.add("genconf_dir/"+name, importIgnored, "", "not a regular file")
+      return nil
+    }
+
+    files = append(files, name)
+    copies = append(copies, genconfFile{path, filepath.Join(dstDir, rel), info.Mode().Perm()})
+    return nil
+  })
+  if err != nil {
+    return nil, nil, fmt.Errorf("Could not read the genconf directory: %s", err.Error())
+  }
+
+  options := make(map[string]interface{})
+  if containsString(files, "config.yaml") {
+    contents, err := ioutil.ReadFile(filepath.Join(cfg.GenconfDir, "config.yaml"))
+    if err != nil {
+      return nil, nil, fmt.Errorf("Could not read the genconf config.yaml: %s", err.Error())
+    }
+    err = yaml.Unmarshal(contents, &options)
+    if err != nil {
+      return nil, nil, fmt.Errorf("Could not parse the genconf config.yaml: %s", err.Error())
+    }
+  }
+
+  for _, name := range files {
+    key := "genconf_dir/" + name
+    option, isScript := genconfScripts[name]
+    if name == "config.yaml" {
+      continue
+    } else if !isScript {
+      p.report.add(key, importIgnored, "", fmt.Sprintf("copied to %s, but the module does not upload it to the bootstrap node", dstDir))
+      continue
+    }
+
+    if _, ok := cfg.DcosConfig[option+"_contents"]; ok {
+      p.report.add(key, importIgnored, "", fmt.Sprintf("`dcos_config.%s_contents` is used instead", option))
+      continue
+    }
+    if _, ok := options[option+"_contents"]; ok {
+      p.report.add(key, importIgnored, "", fmt.Sprintf("`%s_contents` of config.yaml is used instead", option))
+      continue
+    }
+    if _, ok := options[option+"_filename"]; ok {
+      p.report.add("genconf_dir/config.yaml."+option+"_filename", importIgnored, "", fmt.Sprintf("the contents of %s are given instead", name))
+      delete(options, option+"_filename")
+    }
+
+    p.report.add(key, importMapped, "dcos_"+option+"_contents", "")
+    lines = append(lines, fmt.Sprintf(`dcos_%s_contents = "${file("./%s/%s")}"`, option, dstDir, name))
+  }
+
+  if len(options) > 0 && cfg.DcosConfig == nil {
+    cfg.DcosConfig = make(map[string]interface{})
+  }
+  for _, k := range sortedInterfaceKeys(options) {
+    key := "genconf_dir/config.yaml." + k
+    if containsString(genconfManagedOptions, k) {
+      p.report.add(key, importIgnored, "", "the module sets it itself")
+    } else if _, ok := cfg.DcosConfig[k]; ok {
+      p.report.add(key, importIgnored, "", fmt.Sprintf("`dcos_config.%s` is used instead", k))
+    } else {
+      cfg.DcosConfig[k] = options[k]
+      p.genconfOptions[k] = true
+    }
+  }
+
+  if len(lines) > 0 {
+    lines = append([]string{""}, lines...)
+  }
+  return lines, copies, nil
+}
+
+/**
+ * Copies the files of the genconf directory into the project
+ */
+func (p *PluginImportClusterCmdImport) copyGenconf(project *ProjectSandbox, srcDir string) error {
+  if len(p.genconfFiles) == 0 {
+    return nil
+  }
+
+  // The targets are in the genconf directory of the cluster
+  dstDir := strings.SplitN(filepath.ToSlash(p.genconfFiles[0].Target), "/", 2)[0]
+  PrintInfo("Copying %s to %s", Bold(srcDir), Bold(dstDir))
+
+  for _, file := range p.genconfFiles {
+    contents, err := ioutil.ReadFile(file.Source)
+    if err != nil {
+      return fmt.Errorf("Could not copy the genconf directory: %s", err.Error())
+    }
+    err = project.WriteFileWithMode(file.Target, contents, file.Mode)
+    if err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
 func (p *PluginImportClusterCmdImport) importTags(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
   var lines []string = nil
 
   if cfg.Tags != nil && len(cfg.Tags) > 0 {
     lines = append(lines, "", "tags = {")
 
-    for k, v := range cfg.Tags {
+    for _, k := range sortedInterfaceKeys(cfg.Tags) {
+      v := cfg.Tags[k]
+      p.report.add("tags."+k, importMapped, "tags", "")
       lines = append(lines, fmt.Sprintf("  %s = %s,", k, FormatJSON(v)))
     }
 
@@ -276,35 +626,72 @@ func (p *PluginImportClusterCmdImport) importTags(cfg *DcosLaunchInputConfig, pr
 }
 
 func (p *PluginImportClusterCmdImport) importAws(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
-  var lines []string = nil
+  lines := p.importClusterName(cfg)
 
-  if cfg.InstallPrereqs {
-    PrintWarning("Ignoring `install_prereqs` since it's always implied")
+  if cfg.OsName != "" {
+    p.report.add("os_name", importApproximated, "aws_ami",
+      "used as the AMI ID, remove it if you are not using a customized DC/OS AMI")
+    lines = append(
+      lines,
+      fmt.Sprintf(`aws_ami = "%s"`, cfg.OsName),
+    )
   }
 
-  if cfg.DeploymentName != "" {
+  lines = append(lines, p.importDcosVersion(cfg)...)
+
+  if cfg.InstanceType != "" {
+    p.report.add("instance_type", importApproximated,
+      "masters_instance_type, private_agents_instance_type, public_agents_instance_type",
+      "the bootstrap node keeps the module default")
     lines = append(
       lines,
-      fmt.Sprintf(`cluster_name = "%s"`, cfg.DeploymentName),
+      fmt.Sprintf(`masters_instance_type = "%s"`, cfg.InstanceType),
+      fmt.Sprintf(`private_agents_instance_type = "%s"`, cfg.InstanceType),
+      fmt.Sprintf(`public_agents_instance_type = "%s"`, cfg.InstanceType),
     )
   }
 
-  if cfg.OsName != "" {
-    PrintWarning("Consider removing `os_name` if you are not using a customized DC/OS AMI. " +
-      "The Universal Installer already provides the recommended default.")
+  lines = append(lines, p.importNodeCounts(cfg)...)
+
+  return lines, nil
+}
+
+/**
+ * Imports the name of the cluster, for all the onprem platforms
+ */
+func (p *PluginImportClusterCmdImport) importClusterName(cfg *DcosLaunchInputConfig) []string {
+  var lines []string = nil
+
+  if cfg.InstallPrereqs {
+    p.report.add("install_prereqs", importIgnored, "", "it's always implied")
+  }
+
+  if cfg.DeploymentName != "" {
+    p.report.add("deployment_name", importMapped, "cluster_name", "")
     lines = append(
       lines,
-      fmt.Sprintf(`aws_ami = "%s"`, cfg.OsName),
+      fmt.Sprintf(`cluster_name = "%s"`, cfg.DeploymentName),
     )
   }
 
+  return lines
+}
+
+func (p *PluginImportClusterCmdImport) importDcosVersion(cfg *DcosLaunchInputConfig) []string {
+  var lines []string = nil
+
   // Guess DC/OS version
   if cfg.DcosInstallerUrl != "" {
+    p.report.add("installer_url", importMapped, "custom_dcos_download_path", "")
+    if cfg.DcosVersion != "" {
+      p.report.add("dcos_version", importIgnored, "", "`installer_url` is used instead")
+    }
     lines = append(
       lines,
       fmt.Sprintf(`custom_dcos_download_path = "%s"`, cfg.DcosInstallerUrl),
     )
   } else if cfg.DcosVersion != "" {
+    p.report.add("dcos_version", importMapped, "dcos_version", "")
     lines = append(
       lines,
       fmt.Sprintf(`dcos_version = "%s"`, cfg.DcosVersion),
@@ -345,32 +732,145 @@ func (p *PluginImportClusterCmdImport) importAws(cfg *DcosLaunchInputConfig, pro
     }
   }
 
-  if cfg.InstanceType != "" {
-    lines = append(
-      lines,
-      fmt.Sprintf(`masters_instance_type = "%s"`, cfg.InstanceType),
-      fmt.Sprintf(`private_agents_instance_type = "%s"`, cfg.InstanceType),
-      fmt.Sprintf(`public_agents_instance_type = "%s"`, cfg.InstanceType),
-    )
+  return lines
+}
+
+func (p *PluginImportClusterCmdImport) importNodeCounts(cfg *DcosLaunchInputConfig) []string {
+  var lines []string = nil
+
+  // A count explicitly set to zero must not fall back to the module default
+  counts := []struct {
+    key   string
+    value int
+  }{
+    {"num_public_agents", cfg.NumPublicAgents},
+    {"num_private_agents", cfg.NumPrivateAgents},
+    {"num_masters", cfg.NumMasters},
+  }
+  for _, count := range counts {
+    if count.value != 0 || p.configKeys[count.key] {
+      p.report.add(count.key, importMapped, count.key, "")
+      lines = append(lines, fmt.Sprintf(`%s = %d`, count.key, count.value))
+    }
   }
 
-  if cfg.NumPublicAgents != 0 {
-    lines = append(
-      lines,
-      fmt.Sprintf(`num_public_agents = %d`, cfg.NumPublicAgents),
-    )
+  return lines
+}
+
+func (p *PluginImportClusterCmdImport) importGcp(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
+  lines := p.importClusterName(cfg)
+
+  if cfg.SourceImage != "" {
+    image := cfg.SourceImage
+    p.report.add("source_image", importMapped, "image", "")
+    if cfg.ImageProject != "" {
+      image = fmt.Sprintf("%s/%s", cfg.ImageProject, cfg.SourceImage)
+      p.report.add("image_project", importMapped, "image", "")
+    }
+    if cfg.OsName != "" {
+      p.report.add("os_name", importIgnored, "", "`source_image` is used instead")
+    }
+    lines = append(lines, fmt.Sprintf(`image = %s`, FormatJSON(image)))
+  } else if cfg.OsName != "" {
+    p.report.add("os_name", importIgnored, "", "the Universal Installer already provides the recommended image")
   }
-  if cfg.NumPrivateAgents != 0 {
-    lines = append(
-      lines,
-      fmt.Sprintf(`num_private_agents = %d`, cfg.NumPrivateAgents),
-    )
+  if cfg.UsePreemptibleVMs {
+    p.report.add("use_preemptible_vms", importIgnored, "", "it's not supported by the GCP module")
   }
-  if cfg.NumMasters != 0 {
-    lines = append(
-      lines,
-      fmt.Sprintf(`num_masters = %d`, cfg.NumMasters),
-    )
+  if cfg.SshUser != "" {
+    p.report.add("ssh_user", importMapped, "ansible_user", "")
+    lines = append(lines, fmt.Sprintf(`ansible_user = %s`, FormatJSON(cfg.SshUser)))
+  }
+
+  lines = append(lines, p.importDcosVersion(cfg)...)
+  lines = append(lines, p.importNodeSizes(cfg, "machine_type", cfg.MachineType)...)
+  lines = append(lines, p.importNodeCounts(cfg)...)
+
+  return lines, nil
+}
+
+func (p *PluginImportClusterCmdImport) importAzure(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
+  lines := p.importClusterName(cfg)
+
+  location := "West US 2"
+  if cfg.AzureLocation != "" {
+    location = cfg.AzureLocation
+    p.report.add("azure_location", importMapped, "location", "")
+  }
+  lines = append(lines, fmt.Sprintf(`location = %s`, FormatJSON(location)))
+
+  if cfg.OsName != "" {
+    p.report.add("os_name", importIgnored, "", "the Universal Installer already provides the recommended image")
+  }
+  if cfg.SshUser != "" {
+    p.report.add("ssh_user", importMapped, "admin_username", "")
+    lines = append(lines, fmt.Sprintf(`admin_username = %s`, FormatJSON(cfg.SshUser)))
+  }
+
+  lines = append(lines, p.importDcosVersion(cfg)...)
+  lines = append(lines, p.importNodeSizes(cfg, "vm_size", cfg.VmSize)...)
+  lines = append(lines, p.importNodeCounts(cfg)...)
+
+  return lines, nil
+}
+
+/**
+ * Imports the size of the machines and of their disks, which dcos-launch
+ * uses for all the nodes (except for the bootstrap node)
+ */
+func (p *PluginImportClusterCmdImport) importNodeSizes(cfg *DcosLaunchInputConfig, sizeVar string, size string) []string {
+  var lines []string = nil
+
+  // The parameters have the same names as the variables without the role
+  given := map[string]bool{
+    sizeVar:     size != "",
+    "disk_size": cfg.DiskSize != 0,
+    "disk_type": cfg.DiskType != "",
+  }
+  for _, param := range []string{sizeVar, "disk_size", "disk_type"} {
+    if given[param] {
+      p.report.add(param, importApproximated,
+        fmt.Sprintf("masters_%s, private_agents_%s, public_agents_%s", param, param, param),
+        "the bootstrap node keeps the module default")
+    }
+  }
+
+  for _, role := range []string{"masters", "private_agents", "public_agents"} {
+    if size != "" {
+      lines = append(lines, fmt.Sprintf(`%s_%s = %s`, role, sizeVar, FormatJSON(size)))
+    }
+    if cfg.DiskSize != 0 {
+      lines = append(lines, fmt.Sprintf(`%s_disk_size = %d`, role, cfg.DiskSize))
+    }
+    if cfg.DiskType != "" {
+      lines = append(lines, fmt.Sprintf(`%s_disk_type = %s`, role, FormatJSON(cfg.DiskType)))
+    }
+  }
+
+  return lines
+}
+
+/**
+ * Imports the tags as GCP labels, which only allow lowercase letters, digits,
+ * dashes and underscores
+ */
+func (p *PluginImportClusterCmdImport) importLabels(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
+  var lines []string = nil
+
+  if len(cfg.Tags) > 0 {
+    lines = append(lines, "", "labels = {")
+
+    for _, k := range sortedInterfaceKeys(cfg.Tags) {
+      value := fmt.Sprintf("%v", cfg.Tags[k])
+      if gcpLabelValue(k) == k && gcpLabelValue(value) == value {
+        p.report.add("tags."+k, importMapped, "labels", "")
+      } else {
+        p.report.add("tags."+k, importApproximated, "labels", "converted to a valid GCP label")
+      }
+      lines = append(lines, fmt.Sprintf(`  "%s" = "%s",`, gcpLabelValue(k), gcpLabelValue(value)))
+    }
+
+    lines = append(lines, "}")
   }
 
   return lines, nil
@@ -379,8 +879,11 @@ func (p *PluginImportClusterCmdImport) importAws(cfg *DcosLaunchInputConfig, pro
 func (p *PluginImportClusterCmdImport) impotExtraVolumes(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
   var volLines []string = nil
 
-  for _, m := range cfg.AwsBlockDeviceMappings {
-    expr := fmt.Sprintf("%#v", m)
+  for i, m := range cfg.AwsBlockDeviceMappings {
+    key := fmt.Sprintf("aws_block_device_mappings[%d]", i)
+    target := func(attr string) string {
+      return fmt.Sprintf("private_agents_extra_volumes.%s, public_agents_extra_volumes.%s", attr, attr)
+    }
 
     //
     // Mapping from Boto:
@@ -398,39 +901,46 @@ func (p *PluginImportClusterCmdImport) impotExtraVolumes(cfg *DcosLaunchInputCon
             xIops := 0
             xType := ""
 
-            if vSz, ok := devEbsMap["VolumeSize"]; ok {
-              if vSzInt, ok := vSz.(int); ok {
-                xSize = vSzInt
-              } else {
-                PrintWarning("Error in volume '%s': 'VolumeSize' is not an integer", expr)
+            for _, k := range sortedInterfaceKeys(m) {
+              if k == "DeviceName" {
+                p.report.add(key+".DeviceName", importMapped, target("device_name"), "")
+              } else if k != "Ebs" {
+                p.report.add(key+"."+k, importIgnored, "", "not supported by the AWS module")
               }
             }
-            if vIops, ok := devEbsMap["Iops"]; ok {
-              if vIopsInt, ok := vIops.(int); ok {
-                xIops = vIopsInt
-              } else {
-                PrintWarning("Error in volume '%s': 'Iops' is not an integer", expr)
-              }
-            }
-            if vType, ok := devEbsMap["VolumeType"]; ok {
-              if vTypeStr, ok := vType.(string); ok {
-                xType = vTypeStr
-              } else {
-                PrintWarning("Error in volume '%s': 'VolumeType' is not a string", expr)
+
+            for _, k := range sortedInterfaceKeys(devEbsMap) {
+              ebsKey := key + ".Ebs." + k
+              switch k {
+              case "VolumeSize":
+                if vSzInt, ok := devEbsMap[k].(int); ok {
+                  xSize = vSzInt
+                  p.report.add(ebsKey, importMapped, target("size"), "")
+                } else {
+                  p.report.add(ebsKey, importIgnored, "", "not an integer")
+                }
+              case "Iops":
+                if vIopsInt, ok := devEbsMap[k].(int); ok {
+                  xIops = vIopsInt
+                  p.report.add(ebsKey, importMapped, target("iops"), "")
+                } else {
+                  p.report.add(ebsKey, importIgnored, "", "not an integer")
+                }
+              case "VolumeType":
+                if vTypeStr, ok := devEbsMap[k].(string); ok {
+                  xType = vTypeStr
+                  p.report.add(ebsKey, importMapped, target("type"), "")
+                } else {
+                  p.report.add(ebsKey, importIgnored, "", "not a string")
+                }
+              case "SnapshotId", "KmsKeyId", "Encrypted":
+                p.report.add(ebsKey, importIgnored, "", "not supported by the AWS module")
+              case "DeleteOnTermination":
+                p.report.add(ebsKey, importApproximated, "", "terraform always removes the volume during destroy")
+              default:
+                p.report.add(ebsKey, importUnknown, "", "not an EBS parameter")
               }
             }
-            if _, ok := devEbsMap["SnapshotId"]; ok {
-              PrintWarning("Error in volume '%s': 'SnapshotId' is not supported", expr)
-            }
-            if _, ok := devEbsMap["KmsKeyId"]; ok {
-              PrintWarning("Error in volume '%s': 'KmsKeyId' is not supported", expr)
-            }
-            if _, ok := devEbsMap["Encrypted"]; ok {
-              PrintWarning("Error in volume '%s': 'Encrypted' is not supported", expr)
-            }
-            if _, ok := devEbsMap["DeleteOnTermination"]; ok {
-              PrintWarning("Ignoring 'DeleteOnTermination' on volume %s: Terraform will always remove it during destroy", devNameStr)
-            }
 
             volLines = append(volLines, "{")
             volLines = append(volLines, fmt.Sprintf("device_name = %s", FormatJSON(devNameStr)))
@@ -446,16 +956,16 @@ func (p *PluginImportClusterCmdImport) impotExtraVolumes(cfg *DcosLaunchInputCon
             volLines = append(volLines, "},")
 
           } else {
-            PrintWarning("Not importing volume '%s': Invalid 'Ebs'", expr)
+            p.report.add(key, importIgnored, "", "invalid 'Ebs'")
           }
         } else {
-          PrintWarning("Not importing volume '%s': Missing 'Ebs'", expr)
+          p.report.add(key, importIgnored, "", "missing 'Ebs'")
         }
       } else {
-        PrintWarning("Not importing volume '%s': Invalid 'DeviceName'", expr)
+        p.report.add(key, importIgnored, "", "invalid 'DeviceName'")
       }
     } else {
-      PrintWarning("Not importing volume '%s': Missing 'DeviceName'", expr)
+      p.report.add(key, importIgnored, "", "missing 'DeviceName'")
     }
   }
 
@@ -472,7 +982,11 @@ func (p *PluginImportClusterCmdImport) impotExtraVolumes(cfg *DcosLaunchInputCon
   return lines, nil
 }
 
-func (p *PluginImportClusterCmdImport) importOnpremAws(inputConfig *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
+/**
+ * Imports a dcos-launch configuration of the onprem provider, on the
+ * instances of one of the supported platforms
+ */
+func (p *PluginImportClusterCmdImport) importOnprem(inputConfig *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
   var cfgLines []string = nil
 
   // Import sections
@@ -483,7 +997,24 @@ func (p *PluginImportClusterCmdImport) importOnpremAws(inputConfig *DcosLaunchIn
     cfgLines = append(cfgLines, chunk...)
   }
 
-  chunk, err = p.importAws(inputConfig, project)
+  // The genconf `config.yaml` must be merged before the DC/OS variant is
+  // guessed from the `dcos_config`
+  var genconfLines []string
+  if inputConfig.GenconfDir != "" {
+    genconfLines, p.genconfFiles, err = p.importGenconf(inputConfig, project)
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  switch inputConfig.Platform {
+  case "aws":
+    chunk, err = p.importAws(inputConfig, project)
+  case "gcp":
+    chunk, err = p.importGcp(inputConfig, project)
+  case "azure":
+    chunk, err = p.importAzure(inputConfig, project)
+  }
   if err != nil {
     return nil, err
   } else {
@@ -495,34 +1026,256 @@ func (p *PluginImportClusterCmdImport) importOnpremAws(inputConfig *DcosLaunchIn
     return nil, err
   } else {
     cfgLines = append(cfgLines, chunk...)
+    cfgLines = append(cfgLines, genconfLines...)
+  }
+
+  if inputConfig.Platform == "aws" {
+    chunk, err = p.impotExtraVolumes(inputConfig, project)
+    if err != nil {
+      return nil, err
+    } else {
+      cfgLines = append(cfgLines, chunk...)
+    }
   }
 
-  chunk, err = p.impotExtraVolumes(inputConfig, project)
+  // GCP uses labels instead of tags
+  if inputConfig.Platform == "gcp" {
+    chunk, err = p.importLabels(inputConfig, project)
+  } else {
+    chunk, err = p.importTags(inputConfig, project)
+  }
   if err != nil {
     return nil, err
   } else {
     cfgLines = append(cfgLines, chunk...)
   }
 
-  chunk, err = p.importTags(inputConfig, project)
+  return cfgLines, nil
+}
+
+/**
+ * Imports a dcos-launch configuration that deploys the cluster with the
+ * CloudFormation templates of DC/OS (`provider: aws`)
+ */
+func (p *PluginImportClusterCmdImport) importCloudFormation(cfg *DcosLaunchInputConfig, project *ProjectSandbox) ([]string, error) {
+  var cfgLines []string = nil
+  params := cfg.TemplateParameters
+
+  if cfg.TemplateUrl == "" {
+    return nil, fmt.Errorf("Missing `template_url` for the aws provider")
+  }
+  if cfg.DcosConfig != nil {
+    p.report.add("dcos_config", importIgnored, "", "the CloudFormation templates do not use it")
+  }
+
+  // The key pair of the instances is either generated, or an existing EC2 one
+  if _, ok := params["KeyName"]; !ok || cfg.KeyHelper || cfg.SshPrivateKey != "" || cfg.SshPrivateKeyFilename != "" {
+    chunk, err := p.importSSHKeys(cfg, project)
+    if err != nil {
+      return nil, err
+    }
+    cfgLines = append(cfgLines, chunk...)
+  } else {
+    // Don't let the module upload a default public key next to aws_key_name
+    cfgLines = append(cfgLines, `ssh_public_key_file = ""`)
+  }
+
+  if cfg.DeploymentName != "" {
+    p.report.add("deployment_name", importMapped, "cluster_name", "")
+    cfgLines = append(cfgLines, fmt.Sprintf(`cluster_name = "%s"`, cfg.DeploymentName))
+  }
+
+  // The version and the number of masters are given by the template
+  var guessed []string
+  if match := cloudFormationVersionRe.FindStringSubmatch(cfg.TemplateUrl); match != nil {
+    cfgLines = append(cfgLines, fmt.Sprintf(`dcos_version = "%s"`, match[1]))
+  } else {
+    guessed = append(guessed, "the latest DC/OS version")
+    cfgLines = append(cfgLines, fmt.Sprintf(`dcos_version = "%s"`, GetLatestDCOSVersion("open", "2.0.0")))
+  }
+  if match := cloudFormationMastersRe.FindStringSubmatch(cfg.TemplateUrl); match != nil {
+    switch match[1] {
+    case "single":
+      cfgLines = append(cfgLines, `num_masters = 1`)
+    case "multi":
+      cfgLines = append(cfgLines, `num_masters = 3`)
+    default:
+      // The count of `-master-N` and `zen-N` is in one of the other groups
+      for _, count := range match[2:] {
+        if count != "" {
+          cfgLines = append(cfgLines, fmt.Sprintf(`num_masters = %s`, count))
+          break
+        }
+      }
+    }
+  } else {
+    guessed = append(guessed, "the default number of masters of the module")
+  }
+  if len(guessed) > 0 {
+    p.report.add("template_url", importApproximated, "dcos_version, num_masters",
+      fmt.Sprintf("could not be parsed, using %s", strings.Join(guessed, " and ")))
+  } else {
+    p.report.add("template_url", importMapped, "dcos_version, num_masters", "")
+  }
+
+  _, hasLicense := params["LicenseKey"]
+  if cfg.Enterprise {
+    p.report.add("dcos-enterprise", importMapped, "dcos_variant", "")
+  }
+  if cfg.Enterprise || hasLicense || strings.Contains(cfg.TemplateUrl, "/ee/") {
+    cfgLines = append(cfgLines, `dcos_variant = "ee"`)
+  } else {
+    cfgLines = append(cfgLines, `dcos_variant = "open"`)
+  }
+
+  var names []string
+  for name := range params {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  var unsupported []string
+  for _, name := range names {
+    value := fmt.Sprintf("%v", params[name])
+
+    key := "template_parameters." + name
+
+    if name == "AdminLocation" {
+      p.report.add(key, importMapped, "admin_ips", "")
+      cfgLines = append(cfgLines, fmt.Sprintf(`admin_ips = [%s]`, FormatJSON(value)))
+      continue
+    }
+
+    variable, ok := cloudFormationParameters[name]
+    if !ok {
+      reason := "no terraform equivalent"
+      status := importUnknown
+      if r, ok := cloudFormationUnsupported[name]; ok {
+        reason = r
+        status = importIgnored
+      }
+      p.report.add(key, status, "", reason)
+      unsupported = append(unsupported, fmt.Sprintf(`#   %s = %s (%s)`, name, FormatJSON(value), reason))
+      continue
+    }
+
+    if strings.HasPrefix(variable, "num_") {
+      if _, err := strconv.Atoi(value); err != nil {
+        return nil, fmt.Errorf("Could not import template parameter %s: Expected a number, got '%s'", name, value)
+      }
+      cfgLines = append(cfgLines, fmt.Sprintf(`%s = %s`, variable, value))
+    } else {
+      cfgLines = append(cfgLines, fmt.Sprintf(`%s = %s`, variable, FormatJSON(value)))
+    }
+    p.report.add(key, importMapped, variable, "")
+  }
+
+  // Keep the parameters that were not imported in the file, for reference
+  if len(unsupported) > 0 {
+    cfgLines = append(cfgLines,
+      "",
+      "# The following parameters of the CloudFormation template have no",
+      "# equivalent in terraform and were not imported:",
+    )
+    cfgLines = append(cfgLines, unsupported...)
+  }
+
+  chunk, err := p.importTags(cfg, project)
   if err != nil {
     return nil, err
-  } else {
-    cfgLines = append(cfgLines, chunk...)
   }
+  cfgLines = append(cfgLines, chunk...)
 
   return cfgLines, nil
 }
 
+/**
+ * Checks if the given configuration lines define the given attribute
+ */
+func linesDefine(lines []string, name string) bool {
+  for _, line := range lines {
+    trimmed := strings.TrimSpace(line)
+    if strings.HasPrefix(trimmed, name+" ") || strings.HasPrefix(trimmed, name+"=") {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Prints the keys that were not mapped exactly followed by a summary, and
+ * returns the number of these keys
+ */
+func (p *PluginImportClusterCmdImport) printReport(reportFileName string) int {
+  counts := make(map[string]int)
+  for _, entry := range p.report.Entries {
+    counts[entry.Status]++
+  }
+
+  inexact := p.report.getInexact()
+  for _, entry := range inexact {
+    if IsJSONOutput() {
+      PrintEvent("warning", "import_key", map[string]interface{}{
+        "key":    entry.Key,
+        "status": entry.Status,
+        "target": entry.Target,
+      }, "%s", entry.Details)
+    } else if entry.Target != "" {
+      PrintWarning("%s %s as %s: %s", strings.Title(entry.Status), Bold(entry.Key), entry.Target, entry.Details)
+    } else {
+      PrintWarning("%s %s: %s", strings.Title(entry.Status), Bold(entry.Key), entry.Details)
+    }
+  }
+
+  PrintEvent("info", "import_report", map[string]interface{}{
+    "report":       reportFileName,
+    "mapped":       counts[importMapped],
+    "approximated": counts[importApproximated],
+    "ignored":      counts[importIgnored],
+    "unknown":      counts[importUnknown],
+  }, "Imported %d keys: %d mapped, %d approximated, %d ignored, %d unknown (details in %s)",
+    len(p.report.Entries), counts[importMapped], counts[importApproximated],
+    counts[importIgnored], counts[importUnknown], reportFileName)
+
+  return len(inexact)
+}
+
+/**
+ * Returns the cluster id of a deployment name, which can use characters that
+ * are not valid in the names of the module and of the outputs
+ */
+func importClusterId(name string) string {
+  id := strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(name), "-"), "-")
+  if id != "" && (id[0] < 'a' || id[0] > 'z') {
+    id = "c-" + id
+  }
+  if len(id) > 20 {
+    id = strings.TrimRight(id[:20], "-")
+  }
+  return id
+}
+
+func (p *PluginImportClusterCmdImport) writeReport(project *ProjectSandbox, reportFileName string) error {
+  contents, err := yaml.Marshal(p.report)
+  if err != nil {
+    return fmt.Errorf("Could not encode the import report: %s", err.Error())
+  }
+  return project.WriteFile(reportFileName, contents)
+}
+
 func (p *PluginImportClusterCmdImport) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
   var fileName string = "cluster-imported.tf"
   var helpCmdline = "filename.yaml"
   var helpMessage = []interface{}{
     "This command will convert the given dcos-lauch YAML configuration file into",
-    "a terraform deployment module.",
+    "a terraform deployment module. How each key of the configuration was imported",
+    "is written to a report next to the generated file.",
   }
 
   fSet := flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  fAsVariables := fSet.Bool("as-variables", false, "Use variables for the parameters, defined in variables.tf and given in terraform.tfvars")
+  fEnvironment := fSet.String("environment", "", "With -as-variables, give the values in <environment>.tfvars instead of terraform.tfvars")
+  fStrict := fSet.Bool("strict", false, "Fail if any of the keys could not be mapped exactly")
 
   help := fSet.Bool("help", false, "Show this help message")
   fSet.BoolVar(help, "h", false, "Show this help message")
@@ -555,72 +1308,134 @@ func (p *PluginImportClusterCmdImport) Handle(args []string, project *ProjectSan
   if err != nil {
     return fmt.Errorf("Could not parse %s: %s", cfgFilename, err.Error())
   }
+  raw := make(map[string]interface{})
+  yaml.Unmarshal(configContents, &raw)
+  p.configKeys = make(map[string]bool)
+  for key := range raw {
+    p.configKeys[key] = true
+  }
 
-  if inputConfig.DeploymentName != "" {
-    fileName = fmt.Sprintf("cluster-%s.tf", inputConfig.DeploymentName)
+  // The CloudFormation templates always deploy on AWS
+  platform := inputConfig.Platform
+  if inputConfig.Provider == "aws" {
+    platform = "aws"
   }
 
-  // We currently only support provider: onprem and platform: aws
-  if inputConfig.Provider != "onprem" {
-    return fmt.Errorf("Unsupported provider '%s' we only support: onprem", inputConfig.Provider)
+  // A named deployment gets the names of `add-<cloud>-cluster -name`, so it
+  // can be imported next to other clusters
+  p.names = getClusterNames(platform, importClusterId(inputConfig.DeploymentName))
+  if p.names.Id != "" {
+    fileName = p.names.File
   }
-  if inputConfig.Platform != "aws" {
-    return fmt.Errorf("Unsupported platform '%s' we only support: aws", inputConfig.Platform)
+  reportFileName := strings.TrimSuffix(fileName, ".tf") + ".import-report.yaml"
+  p.report = &importReport{Source: cfgFilename, File: fileName}
+  p.genconfOptions = make(map[string]bool)
+  p.writeKeys = nil
+  p.genconfFiles = nil
+  p.report.add("provider", importMapped, "", "")
+  if inputConfig.LaunchConfigVersion != 0 {
+    p.report.add("launch_config_version", importMapped, "", "the version of the dcos-launch format")
   }
-
-  if inputConfig.GenconfDir != "" {
-    return fmt.Errorf("Custom `genconf_dir` is not supported with terraform")
+  if inputConfig.Platform != "" {
+    p.report.add("platform", importMapped, "", "")
   }
 
-  cfgLines, err := p.importOnpremAws(&inputConfig, project)
+  var cfgLines []string
+  switch inputConfig.Provider {
+  case "onprem":
+    if platform != "aws" && platform != "gcp" && platform != "azure" {
+      return fmt.Errorf("Unsupported platform '%s' we only support: aws, gcp, azure", platform)
+    }
+    // Like dcos-launch, the genconf directory is relative to the configuration
+    if inputConfig.GenconfDir != "" && !filepath.IsAbs(inputConfig.GenconfDir) {
+      inputConfig.GenconfDir = filepath.Join(filepath.Dir(cfgFilename), inputConfig.GenconfDir)
+    }
+    cfgLines, err = p.importOnprem(&inputConfig, project)
+  case "aws":
+    cfgLines, err = p.importCloudFormation(&inputConfig, project)
+  default:
+    return fmt.Errorf("Unsupported provider '%s' we only support: onprem, aws", inputConfig.Provider)
+  }
   if err != nil {
     return err
   }
 
   // Collect default lines
-  awsRegion := "us-west-2"
-  if inputConfig.AwsRegion != "" {
-    awsRegion = inputConfig.AwsRegion
-  }
-  preLines := []string{
-    `provider "aws" {`,
-    `  # Change your default region here`,
-    fmt.Sprintf(`  region = "%s"`, awsRegion),
-    `}`,
+  var cloud, providerName, cloudName string
+  var providerLines []string
+  switch platform {
+  case "aws":
+    cloud, providerName, cloudName = "aws", "aws", "AWS"
+    awsRegion := project.GetConfig().GetRegion()
+    if inputConfig.AwsRegion != "" {
+      awsRegion = inputConfig.AwsRegion
+      p.report.add("aws_region", importMapped, "provider.region", "")
+    }
+    providerLines = []string{
+      `  # Change your default region here`,
+      fmt.Sprintf(`  region = "%s"`, awsRegion),
+    }
+  case "gcp":
+    cloud, providerName, cloudName = "gcp", "google", "GCP"
+    gcpRegion := "us-west1"
+    // The region of a zone is its name without the zone suffix
+    if idx := strings.LastIndex(inputConfig.GceZone, "-"); idx > 0 {
+      gcpRegion = inputConfig.GceZone[:idx]
+      p.report.add("gce_zone", importApproximated, "provider.region", "the GCP module spreads the nodes across the zones of the region")
+    }
+    gcpProject := GetGCPProject()
+    projectLine := fmt.Sprintf(`  project = "%s"`, gcpProject)
+    if gcpProject == "" {
+      projectLine = `  # project = "my-gcp-project"`
+    }
+    providerLines = []string{
+      `  # Change your default project and region here`,
+      projectLine,
+      fmt.Sprintf(`  region  = "%s"`, gcpRegion),
+    }
+  case "azure":
+    cloud, providerName, cloudName = "azurerm", "azurerm", "Azure"
+  }
+
+  moduleVersion := project.GetConfig().GetModuleVersion(cloud, func() string {
+    return GetLatestProviderModuleVersion(cloud, "0.2.0")
+  })
+  preLines := p.names.providerLines(providerName, providerLines...)
+  preLines = append(preLines, []string{
     ``,
     `# Used to determine your public IP for forwarding rules`,
-    `data "http" "whatismyip" {`,
+    fmt.Sprintf(`data "http" "%s" {`, p.names.WhatIsMyIP),
     `  url = "http://whatismyip.akamai.com/"`,
     `}`,
     ``,
-    `module "dcos" {`,
-    `  source  = "dcos-terraform/dcos/aws"`,
-    fmt.Sprintf(`  version = "~> %s"`, GetLatestModuleVersion("0.2.0")),
+    fmt.Sprintf(`module "%s" {`, p.names.Module),
+    fmt.Sprintf(`  source  = "dcos-terraform/dcos/%s"`, cloud),
+    fmt.Sprintf(`  version = "~> %s"`, moduleVersion),
     ``,
     `  providers = {`,
-    `    aws = "aws"`,
+    fmt.Sprintf(`    %s = "%s"`, providerName, p.names.providerRef(providerName)),
     `  }`,
     ``,
-  }
-  bodyLines := []string{
-    `  admin_ips                  = ["${data.http.whatismyip.body}/32"]`,
+  }...)
+  var bodyLines []string
+  if !linesDefine(cfgLines, "admin_ips") {
+    bodyLines = append(bodyLines, fmt.Sprintf(`  admin_ips                  = ["${data.http.%s.body}/32"]`, p.names.WhatIsMyIP))
   }
 
   // The output variables must match the JSON file returned by dcos-wheels
-  postLines := []string{
-    `}`,
-    ``,
-    `output "masters-ips" {`,
-    `  value = "${module.dcos.masters-ips}"`,
-    `}`,
-    ``,
-    `output "cluster-address" {`,
-    `  value = "${module.dcos.masters-loadbalancer}"`,
-    `}`,
-    ``,
-    `output "public-agents-loadbalancer" {`,
-    `  value = "${module.dcos.public-agents-loadbalancer}"`,
-    `}`,
+  postLines := append([]string{`}`}, p.names.outputLines()...)
+
+  err = p.report.addRemainingKeys(configContents, &inputConfig)
+  if err != nil {
+    return err
+  }
+  inexact := p.printReport(reportFileName)
+  err = p.writeReport(project, reportFileName)
+  if err != nil {
+    return err
+  }
+  if *fStrict && inexact > 0 {
+    return fmt.Errorf("%d keys of %s could not be mapped exactly, see %s", inexact, cfgFilename, reportFileName)
   }
 
   allLines := append(preLines, bodyLines...)
@@ -629,6 +1444,29 @@ func (p *PluginImportClusterCmdImport) Handle(args []string, project *ProjectSan
 
   contents := []byte(strings.Join(allLines, "\n"))
 
-  PrintInfo("%s%s%s", Bold("Writing "), Bold(Green(fileName)), Bold(" containing information for deploying a DC/OS cluster on AWS"))
-  return project.WriteFormattedTerraformFile(fileName, contents)
+  if *fAsVariables {
+    moduleVars, err := LoadModuleVariables(project, cloud, moduleVersion)
+    if err != nil {
+      return err
+    }
+    contents, err = extractClusterVariables(project, contents, []string{"module", p.names.Module}, p.names.Id, moduleVars, *fEnvironment)
+    if err != nil {
+      return err
+    }
+  }
+
+  if p.writeKeys != nil {
+    err = p.writeKeys()
+    if err != nil {
+      return err
+    }
+  }
+
+  PrintInfo("%s%s%s", Bold("Writing "), Bold(Green(fileName)), Bold(" containing information for deploying a DC/OS cluster on "+cloudName))
+  err = project.WriteFormattedTerraformFile(fileName, contents)
+  if err != nil {
+    return err
+  }
+
+  return p.copyGenconf(project, inputConfig.GenconfDir)
 }
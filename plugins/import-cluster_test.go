@@ -0,0 +1,95 @@
+package plugins
+
+import (
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+)
+
+func TestImportClusterKeyHelper(t *testing.T) {
+  project, cleanup := createTestProject(t)
+  defer cleanup()
+
+  config := "provider: onprem\nplatform: aws\nkey_helper: true\n"
+  cfgFile := filepath.Join(project.GetBaseDir(), "config.yaml")
+  err := ioutil.WriteFile(cfgFile, []byte(config), 0644)
+  if err != nil {
+    t.Fatalf("Could not write the configuration: %s", err.Error())
+  }
+
+  err = (&PluginImportClusterCmdImport{}).Handle([]string{cfgFile}, project, nil)
+  if err != nil {
+    t.Fatalf("Could not import: %s", err.Error())
+  }
+
+  // The keys are written in the project, not in the working directory
+  for _, file := range []string{"cluster-key", "cluster-key.pub"} {
+    if !project.HasFile(file) {
+      t.Errorf("expected %s to be written in the project", file)
+    }
+  }
+}
+
+func TestImportCloudFormationKeyName(t *testing.T) {
+  project, cleanup := createTestProject(t)
+  defer cleanup()
+
+  cfgFile := filepath.Join("testdata", "launch", "cloudformation-keyname.yaml")
+  err := (&PluginImportClusterCmdImport{}).Handle([]string{cfgFile}, project, nil)
+  if err != nil {
+    t.Fatalf("Could not import: %s", err.Error())
+  }
+  err = project.ReloadTerraformProject()
+  if err != nil {
+    t.Fatalf("Could not parse the imported module: %s", err.Error())
+  }
+
+  // The existing EC2 key pair is used, and no public key is uploaded
+  modules := project.GetTerraformResources("module")
+  if len(modules) == 0 {
+    t.Fatalf("expected the module to be imported")
+  }
+  for _, mod := range modules {
+    if mod["aws_key_name"] != "legacy-key" {
+      t.Errorf("expected aws_key_name to be legacy-key, got %v", mod["aws_key_name"])
+    }
+    if value, ok := mod["ssh_public_key_file"]; !ok || value != "" {
+      t.Errorf("expected an empty ssh_public_key_file, got %v", value)
+    }
+  }
+}
+
+func TestImportClusterZeroCounts(t *testing.T) {
+  project, cleanup := createTestProject(t)
+  defer cleanup()
+
+  config := "provider: onprem\nplatform: aws\nkey_helper: true\nnum_masters: 1\nnum_public_agents: 0\n"
+  cfgFile := filepath.Join(project.GetBaseDir(), "config.yaml")
+  err := ioutil.WriteFile(cfgFile, []byte(config), 0644)
+  if err != nil {
+    t.Fatalf("Could not write the configuration: %s", err.Error())
+  }
+
+  err = (&PluginImportClusterCmdImport{}).Handle([]string{cfgFile}, project, nil)
+  if err != nil {
+    t.Fatalf("Could not import: %s", err.Error())
+  }
+  err = project.ReloadTerraformProject()
+  if err != nil {
+    t.Fatalf("Could not parse the imported module: %s", err.Error())
+  }
+
+  // The zero count is written, the missing one is left to the module default
+  modules := project.GetTerraformResources("module")
+  if len(modules) == 0 {
+    t.Fatalf("expected the module to be imported")
+  }
+  for _, mod := range modules {
+    if value, ok := mod["num_public_agents"]; !ok || value != 0 {
+      t.Errorf("expected num_public_agents to be 0, got %v", value)
+    }
+    if value, ok := mod["num_private_agents"]; ok {
+      t.Errorf("expected num_private_agents to be left out, got %v", value)
+    }
+  }
+}
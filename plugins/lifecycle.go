@@ -0,0 +1,170 @@
+package plugins
+
+import (
+  "fmt"
+  "os"
+  "os/signal"
+  "strings"
+  "sync"
+  "syscall"
+
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+/**
+ * Drives the plugins around a terraform invocation, making sure that every
+ * plugin that was started is also stopped (in reverse order), even if the
+ * process fails, panics or gets interrupted.
+ */
+type PluginLifecycle struct {
+  project *ProjectSandbox
+  tf      *TerraformWrapper
+
+  lock        sync.Mutex
+  started     []Plugin
+  busy        bool
+  interrupted bool
+  signals     chan os.Signal
+}
+
+func CreatePluginLifecycle(project *ProjectSandbox, tf *TerraformWrapper) *PluginLifecycle {
+  return &PluginLifecycle{project: project, tf: tf}
+}
+
+/**
+ * Calls BeforeRun on all plugins in order. A plugin is considered started as
+ * soon as its BeforeRun is called, so it gets the chance to clean-up whatever
+ * it managed to start before failing.
+ */
+func (l *PluginLifecycle) Start(plugins []Plugin, initRun bool) error {
+  AtExit(func() {
+    l.stopAndReport(fmt.Errorf("Aborted"))
+  })
+
+  l.signals = make(chan os.Signal, 1)
+  signal.Notify(l.signals, syscall.SIGINT, syscall.SIGTERM)
+  go l.handleSignals()
+
+  for _, plugin := range plugins {
+    l.lock.Lock()
+    if l.interrupted {
+      l.lock.Unlock()
+      return fmt.Errorf("Interrupted")
+    }
+    l.started = append(l.started, plugin)
+    l.busy = true
+    l.lock.Unlock()
+
+    SetEventPlugin(plugin.GetName())
+    err := plugin.BeforeRun(l.project, l.tf, initRun)
+    SetEventPlugin("")
+    l.setBusy(false)
+    if err != nil {
+      return fmt.Errorf("Could not start %s: %s", plugin.GetName(), err.Error())
+    }
+  }
+
+  return nil
+}
+
+/**
+ * Invokes terraform with the given arguments. Interrupts received meanwhile
+ * are handled by terraform itself.
+ */
+func (l *PluginLifecycle) Run(args []string) error {
+  l.setBusy(true)
+  err := l.tf.Invoke(args)
+  l.setBusy(false)
+  return err
+}
+
+/**
+ * Runs the hooks of the given phase of a terraform command. Like for
+ * terraform, interrupts received meanwhile are handled by the hooks, and the
+ * run stops once they complete.
+ */
+func (l *PluginLifecycle) RunHooks(command string, phase string) error {
+  l.setBusy(true)
+  err := RunHooks(l.project, l.tf, command, phase)
+  l.setBusy(false)
+  if err != nil {
+    return err
+  }
+
+  l.lock.Lock()
+  defer l.lock.Unlock()
+  if l.interrupted {
+    return fmt.Errorf("Interrupted")
+  }
+  return nil
+}
+
+/**
+ * Calls AfterRun on the started plugins in reverse order and returns the
+ * errors they reported. It is safe to call this function more than once.
+ */
+func (l *PluginLifecycle) Stop(tfErr error) error {
+  l.lock.Lock()
+  started := l.started
+  l.started = nil
+  if l.signals != nil {
+    signal.Stop(l.signals)
+  }
+  l.lock.Unlock()
+
+  var errs []string
+  for i := len(started) - 1; i >= 0; i-- {
+    plugin := started[i]
+    SetEventPlugin(plugin.GetName())
+    perr := plugin.AfterRun(l.project, l.tf, tfErr)
+    SetEventPlugin("")
+    if perr != nil {
+      errs = append(errs, fmt.Sprintf("Could not finalize %s: %s", plugin.GetName(), perr.Error()))
+    }
+  }
+
+  if len(errs) > 0 {
+    return fmt.Errorf("%s", strings.Join(errs, "; "))
+  }
+  return nil
+}
+
+/**
+ * Stops the plugins when deferred and a panic is in progress, then keeps
+ * panicking
+ */
+func (l *PluginLifecycle) Recover() {
+  if r := recover(); r != nil {
+    l.stopAndReport(fmt.Errorf("Panic: %v", r))
+    panic(r)
+  }
+}
+
+func (l *PluginLifecycle) stopAndReport(tfErr error) {
+  if err := l.Stop(tfErr); err != nil {
+    PrintWarning("%s", err.Error())
+  }
+}
+
+func (l *PluginLifecycle) setBusy(busy bool) {
+  l.lock.Lock()
+  defer l.lock.Unlock()
+  l.busy = busy
+}
+
+func (l *PluginLifecycle) handleSignals() {
+  for range l.signals {
+    l.lock.Lock()
+    if l.busy {
+      // A plugin or terraform is running, stop when it completes
+      l.interrupted = true
+      l.lock.Unlock()
+      continue
+    }
+    l.lock.Unlock()
+
+    PrintWarning("Interrupted, stopping plugins")
+    l.stopAndReport(fmt.Errorf("Interrupted"))
+    Exit(130)
+  }
+}
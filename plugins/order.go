@@ -0,0 +1,104 @@
+package plugins
+
+import (
+  "fmt"
+  "sort"
+  "strings"
+)
+
+/**
+ * Sorts the given plugins so that every plugin comes after its dependencies.
+ * Plugins that are not ordered by a dependency are sorted by priority and
+ * then by name, so the result does not depend on the order of the input.
+ */
+func SortPlugins(plugins []Plugin) ([]Plugin, error) {
+  byName := make(map[string]Plugin)
+  for _, plugin := range plugins {
+    if _, ok := byName[plugin.GetName()]; ok {
+      return nil, fmt.Errorf("Found more than one plugin named %s", plugin.GetName())
+    }
+    byName[plugin.GetName()] = plugin
+  }
+
+  // Collect the edges between the plugins we are sorting, ignoring the
+  // dependencies to plugins that are not present
+  pending := make(map[string]int)
+  dependents := make(map[string][]string)
+  for _, plugin := range plugins {
+    name := plugin.GetName()
+    pending[name] = 0
+    for _, dep := range plugin.GetDependencies() {
+      if _, ok := byName[dep]; !ok || dep == name {
+        continue
+      }
+      pending[name]++
+      dependents[dep] = append(dependents[dep], name)
+    }
+  }
+
+  var ready []Plugin
+  for _, plugin := range plugins {
+    if pending[plugin.GetName()] == 0 {
+      ready = append(ready, plugin)
+    }
+  }
+
+  var ret []Plugin = nil
+  for len(ready) > 0 {
+    sort.SliceStable(ready, func(i, j int) bool {
+      if ready[i].GetPriority() != ready[j].GetPriority() {
+        return ready[i].GetPriority() < ready[j].GetPriority()
+      }
+      return ready[i].GetName() < ready[j].GetName()
+    })
+
+    plugin := ready[0]
+    ready = ready[1:]
+    ret = append(ret, plugin)
+
+    for _, name := range dependents[plugin.GetName()] {
+      pending[name]--
+      if pending[name] == 0 {
+        ready = append(ready, byName[name])
+      }
+    }
+  }
+
+  if len(ret) < len(byName) {
+    return nil, fmt.Errorf("Plugin dependency cycle detected: %s", findPluginCycle(byName, pending))
+  }
+
+  return ret, nil
+}
+
+/**
+ * Walks the dependencies of the plugins that could not be sorted until a
+ * plugin is visited twice, and returns the path of the cycle
+ */
+func findPluginCycle(byName map[string]Plugin, pending map[string]int) string {
+  var names []string
+  for name, n := range pending {
+    if n > 0 {
+      names = append(names, name)
+    }
+  }
+  sort.Strings(names)
+
+  var path []string
+  visited := make(map[string]int)
+  name := names[0]
+  for {
+    if idx, ok := visited[name]; ok {
+      return strings.Join(append(path[idx:], name), " -> ")
+    }
+    visited[name] = len(path)
+    path = append(path, name)
+
+    for _, dep := range byName[name].GetDependencies() {
+      if pending[dep] > 0 && dep != name {
+        name = dep
+        break
+      }
+    }
+  }
+}
@@ -0,0 +1,141 @@
+package plugins
+
+import (
+  "strings"
+  "testing"
+
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+type testPlugin struct {
+  name         string
+  dependencies []string
+  priority     int
+}
+
+func (p *testPlugin) GetName() string {
+  return p.name
+}
+
+func (p *testPlugin) GetDependencies() []string {
+  return p.dependencies
+}
+
+func (p *testPlugin) GetPriority() int {
+  return p.priority
+}
+
+func (p *testPlugin) IsUsed(project *ProjectSandbox) (bool, error) {
+  return true, nil
+}
+
+func (p *testPlugin) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  return nil
+}
+
+func (p *testPlugin) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  return nil
+}
+
+func (p *testPlugin) GetCommands() []PluginCommand {
+  return nil
+}
+
+func pluginNames(plugins []Plugin) string {
+  var names []string
+  for _, plugin := range plugins {
+    names = append(names, plugin.GetName())
+  }
+  return strings.Join(names, ",")
+}
+
+func TestSortPlugins(t *testing.T) {
+  tests := []struct {
+    name     string
+    plugins  []Plugin
+    expected string
+  }{
+    {
+      "sorts by priority and then by name",
+      []Plugin{
+        &testPlugin{"c", nil, 0},
+        &testPlugin{"b", nil, 1},
+        &testPlugin{"a", nil, 0},
+      },
+      "a,c,b",
+    },
+    {
+      "puts the dependencies first",
+      []Plugin{
+        &testPlugin{"dcos-provider", []string{"ssh-agent", "aws"}, 0},
+        &testPlugin{"aws", nil, 1},
+        &testPlugin{"ssh-agent", nil, 2},
+      },
+      "aws,ssh-agent,dcos-provider",
+    },
+    {
+      "ignores the missing dependencies and the self-dependencies",
+      []Plugin{
+        &testPlugin{"b", []string{"missing", "b"}, 0},
+        &testPlugin{"a", []string{"b"}, 0},
+      },
+      "b,a",
+    },
+  }
+
+  for _, test := range tests {
+    sorted, err := SortPlugins(test.plugins)
+    if err != nil {
+      t.Errorf("%s: unexpected error: %s", test.name, err.Error())
+      continue
+    }
+    if names := pluginNames(sorted); names != test.expected {
+      t.Errorf("%s: expected %s, got %s", test.name, test.expected, names)
+    }
+  }
+}
+
+func TestSortPluginsCycle(t *testing.T) {
+  _, err := SortPlugins([]Plugin{
+    &testPlugin{"a", []string{"b"}, 0},
+    &testPlugin{"b", []string{"c"}, 0},
+    &testPlugin{"c", []string{"b"}, 0},
+    &testPlugin{"d", nil, 0},
+  })
+  if err == nil {
+    t.Fatalf("expected an error for a dependency cycle")
+  }
+
+  // The plugin that depends on the cycle is not part of it
+  expected := "Plugin dependency cycle detected: b -> c -> b"
+  if err.Error() != expected {
+    t.Errorf("expected '%s', got '%s'", expected, err.Error())
+  }
+}
+
+func TestSortPluginsDuplicate(t *testing.T) {
+  _, err := SortPlugins([]Plugin{
+    &testPlugin{"a", nil, 0},
+    &testPlugin{"b", []string{"a"}, 0},
+    &testPlugin{"a", nil, 1},
+  })
+  if err == nil {
+    t.Fatalf("expected an error for a duplicate plugin name")
+  }
+
+  expected := "Found more than one plugin named a"
+  if err.Error() != expected {
+    t.Errorf("expected '%s', got '%s'", expected, err.Error())
+  }
+}
+
+func TestFindPluginCycle(t *testing.T) {
+  byName := map[string]Plugin{
+    "a": &testPlugin{"a", []string{"b"}, 0},
+    "b": &testPlugin{"b", []string{"a"}, 0},
+  }
+  cycle := findPluginCycle(byName, map[string]int{"a": 1, "b": 1})
+  if cycle != "a -> b -> a" {
+    t.Errorf("expected 'a -> b -> a', got '%s'", cycle)
+  }
+}
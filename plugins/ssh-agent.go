@@ -3,6 +3,8 @@ package plugins
 import (
   "fmt"
   "os"
+  "path/filepath"
+  "strings"
 
   . "github.com/logrusorgru/aurora"
   . "github.com/mesosphere-incubator/terraform-wheels/utils"
@@ -20,10 +22,19 @@ func (p *PluginSSHAgent) GetName() string {
   return "ssh-agent"
 }
 
+func (p *PluginSSHAgent) GetDependencies() []string {
+  // Start the agent only after the cloud credentials are validated
+  return []string{"dcos-aws", "dcos-azure", "dcos-gcp"}
+}
+
+func (p *PluginSSHAgent) GetPriority() int {
+  return 0
+}
+
 func (p *PluginSSHAgent) IsUsed(project *ProjectSandbox) (bool, error) {
-  // We are loading the SSH-Agent plugin when the dcos-aws module is used
+  // We are loading the SSH-Agent plugin when a dcos cluster module is used
   // and has a public ssh key specified
-  mods := project.GetTerraformResourcesMatching("module", "source", "*dcos-terraform/dcos/aws")
+  mods := getDcosClusterModules(project)
   used := false
   for _, mod := range mods {
     if _, ok := mod["ssh_public_key_file"]; ok {
@@ -62,12 +73,12 @@ func (p *PluginSSHAgent) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper
   tf.SetEnv("SSH_AUTH_SOCK", sshagent.Socket)
   tf.SetEnv("SSH_AGENT_PID", fmt.Sprintf("%d", sshagent.Pid))
 
-  // Find the SSH keys used in the project
+  // Find the SSH keys of all the clusters in the project
   var pubSSHKeys []string = nil
-  mods := project.GetTerraformResourcesMatching("module", "source", "*dcos-terraform/dcos/aws")
+  mods := getDcosClusterModules(project)
   for _, mod := range mods {
     if sshKeyVar, ok := mod["ssh_public_key_file"]; ok {
-      if sshKey, ok := sshKeyVar.(string); ok {
+      if sshKey, ok := sshKeyVar.(string); ok && sshKey != "" && !containsString(pubSSHKeys, sshKey) {
         pubSSHKeys = append(pubSSHKeys, sshKey)
       }
     }
@@ -75,14 +86,22 @@ func (p *PluginSSHAgent) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper
 
   // Validate keys
   for _, sshKey := range pubSSHKeys {
+    if strings.Contains(sshKey, "${") {
+      PrintWarning("Cannot load the ssh key '%s' in ssh-agent, since it's an interpolation. Add the key to your ssh-agent manually", sshKey)
+      continue
+    }
+
     // Check if this is a file in the sandbox that is just missing
     // in which case we will exploit the opportunity to create it
     if project.IsFileInSandbox(sshKey) && !project.HasFile(sshKey) {
       PrintInfo("Found a defined ssh key '%s', but missing from the project directory. Going to create a keypair for you", sshKey)
 
-      fPrivateKey := project.GetFilePath(GetPrivateKeyNameFromPublic(sshKey))
-      fPublicKey := project.GetFilePath(sshKey)
-      err := CreateRSAKeyPair(fPrivateKey, fPublicKey)
+      // The sandbox writes the files relative to the project directory
+      fPublicKey := sshKey
+      if filepath.IsAbs(sshKey) {
+        fPublicKey, _ = filepath.Rel(project.GetBaseDir(), sshKey)
+      }
+      err := project.WriteRSAKeyPair(GetPrivateKeyNameFromPublic(fPublicKey), fPublicKey)
       if err != nil {
         return fmt.Errorf("Could not create RSA keypair: %s", err.Error())
       }
@@ -96,7 +115,7 @@ func (p *PluginSSHAgent) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper
     }
 
     // Add it to the SSH agent
-    PrintInfo("Loaded private key %s in ssh-agent", Bold(privKey))
+    PrintEvent("info", "key_loaded", map[string]interface{}{"key": privKey}, "Loaded private key %s in ssh-agent", Bold(privKey))
     err = sshagent.AddKey(privKey)
     if err != nil {
       return err
@@ -107,7 +126,13 @@ func (p *PluginSSHAgent) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper
 }
 
 func (p *PluginSSHAgent) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  // The agent might not be started if BeforeRun failed early
+  if p.agent == nil {
+    return nil
+  }
+
   err := p.agent.Stop()
+  p.agent = nil
   return err
 }
 
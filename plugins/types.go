@@ -13,6 +13,14 @@ type PluginCommand interface {
 type Plugin interface {
 	GetName() string
 
+	// The names of the plugins that must be started before this one. Plugins
+	// that are not used in the project are ignored.
+	GetDependencies() []string
+
+	// Plugins with a lower priority are started first, when their
+	// dependencies do not already define the order
+	GetPriority() int
+
 	IsUsed(project *ProjectSandbox) (bool, error)
 	BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error
 	AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error
@@ -0,0 +1,189 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+  "strings"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+type PluginUpdateCluster struct {
+}
+
+func CreatePluginUpdateCluster() *PluginUpdateCluster {
+  return &PluginUpdateCluster{}
+}
+
+func (p *PluginUpdateCluster) GetName() string {
+  return "update-cluster"
+}
+
+func (p *PluginUpdateCluster) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginUpdateCluster) GetPriority() int {
+  return 0
+}
+
+func (p *PluginUpdateCluster) IsUsed(project *ProjectSandbox) (bool, error) {
+  return false, nil
+}
+
+func (p *PluginUpdateCluster) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  return nil
+}
+
+func (p *PluginUpdateCluster) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  return nil
+}
+
+func (p *PluginUpdateCluster) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginUpdateClusterCmdUpdate{},
+  }
+}
+
+type PluginUpdateClusterCmdUpdate struct {
+}
+
+func (p *PluginUpdateClusterCmdUpdate) GetName() string {
+  return "update-cluster"
+}
+
+func (p *PluginUpdateClusterCmdUpdate) GetDescription() string {
+  return "Changes the parameters of an existing DC/OS cluster definition"
+}
+
+func (p *PluginUpdateClusterCmdUpdate) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  var fSet, fUnset MultiValueFlag
+  flags := flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  flags.Var(&fSet, "set", "Set a parameter in name=value format (use multiple times to set multiple parameters)")
+  flags.Var(&fUnset, "unset", "Remove a parameter, so the module default is used (use multiple times to remove multiple parameters)")
+  fModule := flags.String("module", "", "The name of the cluster module to change, if the project has more than one")
+  fYes := flags.Bool("yes", false, "Write the changes without asking for confirmation")
+  help := flags.Bool("help", false, "Show this help message")
+  flags.BoolVar(help, "h", false, "Show this help message")
+  err := flags.Parse(args)
+  if err != nil {
+    return err
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), "", []interface{}{
+      "This command will change the parameters of the DC/OS cluster module in your",
+      "project, keeping the rest of the file (including comments) as it is. Values",
+      "can be strings or HCL lists and maps (ex. '[\"1.2.3.4/32\"]'). Numbers and",
+      "booleans are only written unquoted for the numeric and boolean parameters.",
+      "Parameters given by a variable (ex. \"${var.num_masters}\") must be changed",
+      "in your tfvars file instead.",
+    }, flags)
+    return nil
+  }
+
+  if len(fSet.Values) == 0 && len(fUnset.Values) == 0 {
+    return fmt.Errorf("Nothing to do, use -set or -unset to change the cluster parameters")
+  }
+
+  mod, err := findClusterModule(project, *fModule)
+  if err != nil {
+    return err
+  }
+  modName := mod["_name"].(string)
+  fileName := mod["_file"].(string)
+  block := []string{"module", modName}
+
+  original, err := project.ReadFile(fileName)
+  if err != nil {
+    return fmt.Errorf("Could not read %s: %s", fileName, err.Error())
+  }
+
+  // The values are checked like the flags of add-aws-cluster
+  params := make(map[string]ParamSpec)
+  if getParams, ok := dcosClusterParams[getClusterModuleProvider(mod)]; ok {
+    params = getParams()
+  }
+  invalid := make(map[string][]string)
+
+  var errs []string
+  content := original
+  for _, value := range fSet.Values {
+    kv := strings.SplitN(value, "=", 2)
+    if len(kv) < 2 || kv[0] == "" {
+      errs = append(errs, fmt.Sprintf("Could not parse -set '%s': Expected name=value format", value))
+      continue
+    }
+    if kv[0] == "source" || kv[0] == "version" {
+      errs = append(errs, fmt.Sprintf("Could not set %s: Changing the module %s is not supported", kv[0], kv[0]))
+      continue
+    }
+
+    // The values given by a variable (ex. imported with -as-variables) are
+    // changed in the tfvars files, not replaced by a literal
+    if str, ok := mod[kv[0]].(string); ok {
+      if match := varReferenceRe.FindStringSubmatch(str); match != nil {
+        errs = append(errs, fmt.Sprintf("Could not set %s: The value is given by the variable %s, change it in your tfvars file instead", kv[0], match[1]))
+        continue
+      }
+    }
+
+    // The parameters without a type are strings
+    spec := params[kv[0]]
+    node := spec.ParseHCLValue(kv[1])
+    if problems := spec.ValidateHCLValue(node); len(problems) > 0 {
+      invalid[kv[0]] = append(invalid[kv[0]], problems...)
+      continue
+    }
+
+    updated, err := SetHCLAttribute(content, block, kv[0], node)
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not set %s: %s", kv[0], err.Error()))
+      continue
+    }
+    content = updated
+  }
+  for _, name := range fUnset.Values {
+    existing, err := GetHCLAttribute(content, block, name)
+    if err == nil && existing == nil {
+      err = fmt.Errorf("The parameter is not defined")
+    }
+    if err == nil {
+      content, err = UnsetHCLAttribute(content, block, name)
+    }
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not unset %s: %s", name, err.Error()))
+    }
+  }
+
+  if len(errs) > 0 {
+    return fmt.Errorf("%s", strings.Join(errs, "; "))
+  }
+  if err := InvalidParamsError(invalid); err != nil {
+    return err
+  }
+
+  diff := UnifiedDiff("a/"+fileName, "b/"+fileName, original, content, 3)
+  if diff == "" {
+    PrintInfo("%s is already up to date", fileName)
+    return nil
+  }
+
+  // In dry-run the sandbox shows the changes itself
+  if !project.IsDryRun() {
+    if IsJSONOutput() {
+      PrintEvent("info", "file_diff", map[string]interface{}{"file": fileName, "diff": diff}, "Changes to %s", fileName)
+    } else {
+      PrintInfo("The following changes will be made to %s:", Bold(fileName))
+      PrintDiff(diff)
+    }
+
+    if !*fYes && !ReadYN(fmt.Sprintf("Write the changes to %s?", fileName)) {
+      PrintWarning("Aborted, %s was not changed", fileName)
+      return nil
+    }
+  }
+
+  return project.WriteFile(fileName, content)
+}
@@ -0,0 +1,40 @@
+package plugins
+
+import (
+  "io/ioutil"
+  "strings"
+  "testing"
+)
+
+func TestUpdateClusterSetVariable(t *testing.T) {
+  project, cleanup := createTestProject(t)
+  defer cleanup()
+
+  original := strings.Join([]string{
+    `module "dcos" {`,
+    `  source  = "dcos-terraform/dcos/aws"`,
+    `  version = "~> 0.2.0"`,
+    ``,
+    `  num_masters = "${var.num_masters}"`,
+    `}`,
+    ``,
+  }, "\n")
+  err := ioutil.WriteFile(project.GetFilePath("main.tf"), []byte(original), 0644)
+  if err != nil {
+    t.Fatalf("Could not write the module: %s", err.Error())
+  }
+  err = project.ReloadTerraformProject()
+  if err != nil {
+    t.Fatalf("Could not parse the module: %s", err.Error())
+  }
+
+  // The variable reference must not be replaced by a literal
+  err = (&PluginUpdateClusterCmdUpdate{}).Handle([]string{"-set", "num_masters=3", "-yes"}, project, nil)
+  if err == nil || !strings.Contains(err.Error(), "variable num_masters") {
+    t.Errorf("expected an error naming the variable, got %v", err)
+  }
+  contents, _ := project.ReadFile("main.tf")
+  if string(contents) != original {
+    t.Errorf("expected main.tf to be unchanged, got:\n%s", string(contents))
+  }
+}
@@ -4,6 +4,20 @@ import (
   "encoding/json"
   "fmt"
   "io/ioutil"
+  "os"
+  "path/filepath"
+  "regexp"
+  "sort"
+  "strconv"
+  "strings"
+
+  "github.com/gobwas/glob"
+  "github.com/hashicorp/hcl"
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+  "gopkg.in/hlandau/passlib.v1"
+  "gopkg.in/hlandau/passlib.v1/abstract"
+  "gopkg.in/hlandau/passlib.v1/hash/sha2crypt"
 )
 
 func ToJson(iface interface{}) string {
@@ -17,6 +31,326 @@ func ToJsonString(iface interface{}) string {
   return string(sv)
 }
 
+// The sources of the dcos-terraform cluster modules, per cloud provider
+var dcosModuleSources map[string]string = map[string]string{
+  "aws":     "*dcos-terraform/dcos/aws",
+  "azurerm": "*dcos-terraform/dcos/azurerm",
+  "gcp":     "*dcos-terraform/dcos/gcp",
+}
+
+// The types of the parameters of the cluster modules, per cloud provider
+var dcosClusterParams map[string]func() map[string]ParamSpec = map[string]func() map[string]ParamSpec{
+  "aws":     awsClusterParams,
+  "azurerm": azureClusterParams,
+  "gcp":     gcpClusterParams,
+}
+
+/**
+ * Returns the cloud provider of a dcos-terraform cluster module, or an empty
+ * string if the source is not known
+ */
+func getClusterModuleProvider(mod map[string]interface{}) string {
+  source, _ := mod["source"].(string)
+  for _, provider := range sortedStringKeys(dcosModuleSources) {
+    if glob.MustCompile(dcosModuleSources[provider]).Match(source) {
+      return provider
+    }
+  }
+  return ""
+}
+
+/**
+ * Returns the dcos-terraform cluster modules of all the cloud providers,
+ * sorted by name
+ */
+func getDcosClusterModules(project *ProjectSandbox) []map[string]interface{} {
+  var mods []map[string]interface{}
+  for _, provider := range sortedStringKeys(dcosModuleSources) {
+    mods = append(mods, project.GetTerraformResourcesMatching("module", "source", dcosModuleSources[provider])...)
+  }
+  sort.Slice(mods, func(i, j int) bool {
+    return mods[i]["_name"].(string) < mods[j]["_name"].(string)
+  })
+  return mods
+}
+
+/**
+ * Returns the cluster module with the given name, or the only cluster module
+ * of the project if no name is given
+ */
+func findClusterModule(project *ProjectSandbox, name string) (map[string]interface{}, error) {
+  mods := getDcosClusterModules(project)
+  if len(mods) == 0 {
+    return nil, fmt.Errorf("Could not find a DC/OS cluster module in the project. Use `add-aws-cluster` to create one")
+  }
+
+  var names []string
+  for _, mod := range mods {
+    modName := mod["_name"].(string)
+    if modName == name {
+      return mod, nil
+    }
+    names = append(names, modName)
+  }
+
+  if name != "" {
+    return nil, fmt.Errorf("Could not find the cluster module '%s'. Available modules: %s", name, strings.Join(names, ", "))
+  }
+  if len(mods) > 1 {
+    return nil, fmt.Errorf("The project has more than one cluster module, use -module to select one of: %s", strings.Join(names, ", "))
+  }
+  return mods[0], nil
+}
+
+/**
+ * The names of the module, the files and the outputs of a cluster. A cluster
+ * without an id keeps the names of a project with a single cluster.
+ */
+type clusterNames struct {
+  Id           string
+  Module       string
+  File         string
+  SSHKey       string
+  OutputPrefix string
+  WhatIsMyIP   string
+}
+
+func getClusterNames(cloud string, id string) clusterNames {
+  if id == "" {
+    return clusterNames{"", "dcos", fmt.Sprintf("cluster-%s.tf", cloud), "cluster-key.pub", "", "whatismyip"}
+  }
+  return clusterNames{
+    id,
+    fmt.Sprintf("dcos-%s", id),
+    fmt.Sprintf("cluster-%s-%s.tf", cloud, id),
+    fmt.Sprintf("cluster-%s-key.pub", id),
+    fmt.Sprintf("%s-", id),
+    fmt.Sprintf("whatismyip-%s", id),
+  }
+}
+
+/**
+ * Returns the default name of the cluster
+ */
+func (n clusterNames) clusterName() string {
+  if n.Id != "" {
+    return n.Id
+  }
+  return "my-dcos-demo"
+}
+
+/**
+ * Returns the lines of a cloud provider block, which is aliased to the
+ * cluster id so that every cluster can have its own provider
+ */
+func (n clusterNames) providerLines(provider string, lines ...string) []string {
+  ret := []string{fmt.Sprintf(`provider "%s" {`, provider)}
+  if n.Id != "" {
+    ret = append(ret, fmt.Sprintf(`  alias = "%s"`, n.Id))
+  }
+  ret = append(ret, lines...)
+  return append(ret, `}`)
+}
+
+/**
+ * Returns the provider reference to pass to the cluster module
+ */
+func (n clusterNames) providerRef(provider string) string {
+  if n.Id != "" {
+    return fmt.Sprintf("%s.%s", provider, n.Id)
+  }
+  return provider
+}
+
+/**
+ * Returns the outputs of the cluster module
+ */
+func (n clusterNames) outputLines() []string {
+  var lines []string
+  for _, output := range [][2]string{
+    {"masters-ips", "masters-ips"},
+    {"cluster-address", "masters-loadbalancer"},
+    {"public-agents-loadbalancer", "public-agents-loadbalancer"},
+  } {
+    lines = append(lines, []string{
+      ``,
+      fmt.Sprintf(`output "%s%s" {`, n.OutputPrefix, output[0]),
+      fmt.Sprintf(`  value = "${module.%s.%s}"`, n.Module, output[1]),
+      `}`,
+    }...)
+  }
+  return lines
+}
+
+var validateClusterId func(string) error = validatePattern(`^[a-z][a-z0-9-]{0,19}$`, "cluster id (lowercase letters, digits and dashes, up to 20 characters)")
+
+/**
+ * Moves the values of the cluster module to variables, defined in
+ * `variables.tf` and given in the tfvars file of the environment, and
+ * returns the updated cluster file
+ */
+func extractClusterVariables(project *ProjectSandbox, contents []byte, block []string, id string, vars []ModuleVariable, environment string) ([]byte, error) {
+  prefix := ""
+  if id != "" {
+    prefix = strings.ReplaceAll(id, "-", "_") + "_"
+  }
+
+  // The ssh key is used by the ssh-agent plugin, so it must be a literal
+  contents, extracted, err := ExtractHCLVariables(contents, block, prefix, vars, []string{"ssh_public_key_file"})
+  if err != nil {
+    return nil, fmt.Errorf("Could not extract the variables: %s", err.Error())
+  }
+
+  tfvarsFile := DefaultTfvarsFile
+  if environment != "" {
+    tfvarsFile = environment + ".tfvars"
+  }
+  err = project.WriteVariables(extracted, tfvarsFile)
+  if err != nil {
+    return nil, err
+  }
+
+  PrintInfo("%s%s%s%s", Bold("Writing "), Bold(Green(VariablesFile)), Bold(" and "), Bold(Green(tfvarsFile)))
+  if environment != "" {
+    PrintInfo("Use %s to deploy the %s environment", Bold(fmt.Sprintf("-var-file=%s", tfvarsFile)), environment)
+  }
+  return contents, nil
+}
+
+/**
+ * Hashes the DC/OS superuser password the way DC/OS expects it
+ */
+func hashSuperuserPassword(password string) (string, error) {
+  ctx := &passlib.Context{
+    Schemes: []abstract.Scheme{
+      sha2crypt.NewCrypter512(656000),
+    },
+  }
+
+  hash, err := ctx.Hash(password)
+  if err != nil {
+    return "", fmt.Errorf("Could not encode password: %s", err.Error())
+  }
+  return hash, nil
+}
+
+/**
+ * Shows the next steps after a cluster file was created
+ */
+func printClusterInstructions(cloud string, fileName string) {
+  PrintMessage([]interface{}{
+    "",
+    Bold(fmt.Sprintf("You can now deploy a cluster on %s", cloud)),
+    "",
+    fmt.Sprintf("The file %s was generated in your project directory and it describes", fileName),
+    "the resources that are needed to be created in order to deploy a DC/OS",
+    fmt.Sprintf("cluster on %s. Your next steps are:", cloud),
+    "",
+    fmt.Sprintf("  1. Open %s and adjust the configuration to your needs", fileName),
+    fmt.Sprintf("  2. %s plan -out=plan.out  # To prepare your deployment", os.Args[0]),
+    fmt.Sprintf("  3. %s apply plan.out      # To create the deployment", os.Args[0]),
+    "",
+  })
+}
+
+/**
+ * Lists the given presets together with the parameters they set
+ */
+func printPresets(presets []ClusterPreset) {
+  var lines []interface{}
+  for _, preset := range presets {
+    lines = append(lines, fmt.Sprintf("%s (%s)", Bold(preset.Name), preset.Source))
+    if preset.Description != "" {
+      lines = append(lines, fmt.Sprintf("  %s", preset.Description))
+    }
+
+    values := preset.FlagValues()
+    var names []string
+    for name := range values {
+      names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+      for _, value := range values[name] {
+        lines = append(lines, fmt.Sprintf("    -%s=%s", name, value))
+      }
+    }
+    lines = append(lines, "")
+  }
+  PrintMessage(lines)
+}
+
+func containsString(values []string, value string) bool {
+  for _, v := range values {
+    if v == value {
+      return true
+    }
+  }
+  return false
+}
+
+func sortedStringKeys(m map[string]string) []string {
+  var keys []string
+  for k := range m {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+  return keys
+}
+
+func sortedInterfaceKeys(m map[string]interface{}) []string {
+  var keys []string
+  for k := range m {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+  return keys
+}
+
+/**
+ * Validation functions for the interactive prompts
+ */
+func validateNumber(min int) func(string) error {
+  return func(value string) error {
+    n, err := strconv.Atoi(value)
+    if err != nil {
+      return fmt.Errorf("'%s' is not a number", value)
+    }
+    if n < min {
+      return fmt.Errorf("The value must be at least %d", min)
+    }
+    return nil
+  }
+}
+
+func validateOneOf(choices ...string) func(string) error {
+  return func(value string) error {
+    for _, c := range choices {
+      if c == value {
+        return nil
+      }
+    }
+    return fmt.Errorf("Please use one of: %s", strings.Join(choices, ", "))
+  }
+}
+
+func validatePattern(pattern string, description string) func(string) error {
+  re := regexp.MustCompile(pattern)
+  return func(value string) error {
+    if !re.MatchString(value) {
+      return fmt.Errorf("'%s' is not a valid %s", value, description)
+    }
+    return nil
+  }
+}
+
+func validateFileExists(value string) error {
+  if _, err := os.Stat(value); err != nil {
+    return fmt.Errorf("Could not find the file '%s'", value)
+  }
+  return nil
+}
+
 func interfaceToLines(iface interface{}, path string, lines []string) []string {
   var ret []string = append(lines, "")
   var segLines []string
@@ -100,3 +434,126 @@ func LoadServiceJsonToConfigLines(filename string) ([]string, error) {
 
   return lines, nil
 }
+
+var varReferenceRe = regexp.MustCompile(`^\$\{var\.([a-zA-Z0-9_-]+)\}$`)
+
+/**
+ * Reads the values of the variables: the defaults of the variable blocks,
+ * overridden like terraform does by terraform.tfvars, then by the
+ * *.auto.tfvars files and then by the given tfvars files (in order). Empty
+ * file names are ignored.
+ */
+func readVariableValues(project *ProjectSandbox, varFiles ...string) (map[string]interface{}, error) {
+  values := make(map[string]interface{})
+  for name, variable := range project.GetTerraformResources("variable") {
+    if def, ok := variable["default"]; ok {
+      values[name] = def
+    }
+  }
+
+  var files []string
+  if project.HasFile(DefaultTfvarsFile) {
+    files = append(files, project.GetFilePath(DefaultTfvarsFile))
+  }
+  autoFiles, _ := filepath.Glob(project.GetFilePath("*.auto.tfvars"))
+  files = append(files, autoFiles...)
+  for _, varFile := range varFiles {
+    if varFile == "" {
+      continue
+    }
+    if !filepath.IsAbs(varFile) {
+      varFile = project.GetFilePath(varFile)
+    }
+    files = append(files, varFile)
+  }
+
+  for _, file := range files {
+    content, err := ioutil.ReadFile(file)
+    if err != nil {
+      return nil, fmt.Errorf("Could not read %s: %s", file, err.Error())
+    }
+    fileValues := make(map[string]interface{})
+    err = hcl.Unmarshal(content, &fileValues)
+    if err != nil {
+      return nil, fmt.Errorf("Could not parse %s: %s", file, err.Error())
+    }
+    for k, v := range fileValues {
+      values[k] = v
+    }
+  }
+
+  return values, nil
+}
+
+/**
+ * Returns the tfvars files given with `-var-file` in terraform arguments
+ */
+func getVarFileArgs(args []string) []string {
+  var files []string
+  for i := 0; i < len(args); i++ {
+    if !strings.HasPrefix(args[i], "-") {
+      continue
+    }
+    arg := strings.TrimLeft(args[i], "-")
+    if arg == "var-file" && i+1 < len(args) {
+      files = append(files, args[i+1])
+      i++
+    } else if strings.HasPrefix(arg, "var-file=") {
+      files = append(files, strings.TrimPrefix(arg, "var-file="))
+    }
+  }
+  return files
+}
+
+/**
+ * Replaces a `${var.name}` reference with the value of the variable
+ */
+func resolveVariable(value interface{}, variables map[string]interface{}) interface{} {
+  str, ok := value.(string)
+  if !ok {
+    return value
+  }
+  if match := varReferenceRe.FindStringSubmatch(str); match != nil {
+    if v, ok := variables[match[1]]; ok {
+      return v
+    }
+  }
+  return value
+}
+
+func hclInt(value interface{}) (int, error) {
+  switch tv := value.(type) {
+  case int:
+    return tv, nil
+  case int64:
+    return int(tv), nil
+  case float64:
+    return int(tv), nil
+  case string:
+    n, err := strconv.Atoi(tv)
+    if err != nil {
+      return 0, fmt.Errorf("Expected a number, got '%s'", tv)
+    }
+    return n, nil
+  }
+  return 0, fmt.Errorf("Expected a number, got %v", value)
+}
+
+/**
+ * Returns the objects of a decoded HCL value, that can be nested in lists
+ */
+func hclMaps(value interface{}) []map[string]interface{} {
+  switch tv := value.(type) {
+  case map[string]interface{}:
+    return []map[string]interface{}{tv}
+  case []map[string]interface{}:
+    return tv
+  case []interface{}:
+    var ret []map[string]interface{}
+    for _, item := range tv {
+      ret = append(ret, hclMaps(item)...)
+    }
+    return ret
+  }
+  return nil
+}
@@ -0,0 +1,47 @@
+package plugins
+
+import (
+  "io/ioutil"
+  "reflect"
+  "testing"
+
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+func TestGetVarFileArgs(t *testing.T) {
+  args := []string{"apply", "-var-file", "staging.tfvars", "-auto-approve", "--var-file=secrets.tfvars", "var-file=plan"}
+  expected := []string{"staging.tfvars", "secrets.tfvars"}
+  if files := getVarFileArgs(args); !reflect.DeepEqual(files, expected) {
+    t.Errorf("expected %v, got %v", expected, files)
+  }
+}
+
+func TestReadVariableValues(t *testing.T) {
+  project, cleanup := createTestProject(t)
+  defer cleanup()
+
+  files := map[string]string{
+    DefaultTfvarsFile:    "region = \"us-east-1\"\nnum_masters = 1\nowner = \"ops\"\n",
+    "cluster.auto.tfvars": "num_masters = 3\n",
+    "staging.tfvars":      "region = \"us-west-2\"\n",
+  }
+  for name, content := range files {
+    err := ioutil.WriteFile(project.GetFilePath(name), []byte(content), 0644)
+    if err != nil {
+      t.Fatalf("Could not write %s: %s", name, err.Error())
+    }
+  }
+
+  values, err := readVariableValues(project, "staging.tfvars")
+  if err != nil {
+    t.Fatalf("Could not read the variables: %s", err.Error())
+  }
+  expected := map[string]interface{}{
+    "region":      "us-west-2",
+    "num_masters": 3,
+    "owner":       "ops",
+  }
+  if !reflect.DeepEqual(values, expected) {
+    t.Errorf("expected %v, got %v", expected, values)
+  }
+}
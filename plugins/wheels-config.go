@@ -0,0 +1,207 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+  "io/ioutil"
+  "os"
+
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+type PluginWheelsConfig struct {
+}
+
+func CreatePluginWheelsConfig() *PluginWheelsConfig {
+  return &PluginWheelsConfig{}
+}
+
+func (p *PluginWheelsConfig) GetName() string {
+  return "wheels-config"
+}
+
+func (p *PluginWheelsConfig) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginWheelsConfig) GetPriority() int {
+  return 0
+}
+
+func (p *PluginWheelsConfig) IsUsed(project *ProjectSandbox) (bool, error) {
+  return false, nil
+}
+
+func (p *PluginWheelsConfig) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  return nil
+}
+
+func (p *PluginWheelsConfig) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  return nil
+}
+
+func (p *PluginWheelsConfig) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginWheelsConfigCmdConfig{},
+  }
+}
+
+type PluginWheelsConfigCmdConfig struct {
+}
+
+func (p *PluginWheelsConfigCmdConfig) GetName() string {
+  return "wheels-config"
+}
+
+func (p *PluginWheelsConfigCmdConfig) GetDescription() string {
+  return "Shows or changes the terraform-wheels configuration"
+}
+
+func (p *PluginWheelsConfigCmdConfig) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  var helpCmdline = "list | get <key> | set <key> <value> | unset <key>"
+  var helpMessage = []interface{}{
+    "This command will show or change the configuration that is used as default",
+    "by the other commands. The project configuration is stored in " + ProjectConfigFile,
+    "and it overrides the user configuration in ~/.config/terraform-wheels/config.yaml",
+    "",
+    "Available keys:",
+    "  region, owner, expiration, tags.<name>, terraform_version,",
+    "  module_versions.<provider>, plugins.enabled, plugins.disabled,",
+    "  budget.hourly, budget.total",
+    "  (hooks must be edited in the configuration file)",
+    "",
+    "Lists are given as comma-separated values.",
+  }
+
+  fSet := flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  fUser := fSet.Bool("user", false, "Use the user configuration instead of the project configuration")
+
+  help := fSet.Bool("help", false, "Show this help message")
+  fSet.BoolVar(help, "h", false, "Show this help message")
+  err := fSet.Parse(args)
+  if err != nil {
+    return err
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), helpCmdline, helpMessage, fSet)
+    return nil
+  }
+
+  path := project.GetFilePath(ProjectConfigFile)
+  if *fUser {
+    path, err = GetUserConfigPath()
+    if err != nil {
+      return err
+    }
+  }
+
+  cArgs := fSet.Args()
+  if len(cArgs) < 1 {
+    PrintHelp(p.GetName(), helpCmdline, helpMessage, fSet)
+    return fmt.Errorf("Please specify the action to perform")
+  }
+
+  switch cArgs[0] {
+  case "list", "get":
+    // Without -user we show the effective configuration
+    cfg := project.GetConfig()
+    if *fUser {
+      cfg, err = ReadWheelsConfigFile(path)
+      if err != nil {
+        return err
+      }
+    }
+
+    values, err := cfg.Flatten()
+    if err != nil {
+      return fmt.Errorf("Could not read configuration: %s", err.Error())
+    }
+
+    if cArgs[0] == "get" {
+      if len(cArgs) < 2 {
+        return fmt.Errorf("Please specify the key to get")
+      }
+      if v, ok := values[cArgs[1]]; ok {
+        p.printValue(cArgs[1], v, false)
+      }
+      return nil
+    }
+
+    for _, k := range SortedConfigKeys(values) {
+      p.printValue(k, values[k], true)
+    }
+    return nil
+
+  case "set":
+    if len(cArgs) < 3 {
+      return fmt.Errorf("Please specify the key and the value to set")
+    }
+    written, err := p.updateConfig(project, path, *fUser, cArgs[1], &cArgs[2])
+    if err != nil {
+      return err
+    }
+    if written {
+      PrintInfo("Updated %s in %s", Bold(cArgs[1]), Bold(path))
+    }
+    return nil
+
+  case "unset":
+    if len(cArgs) < 2 {
+      return fmt.Errorf("Please specify the key to unset")
+    }
+    written, err := p.updateConfig(project, path, *fUser, cArgs[1], nil)
+    if err != nil {
+      return err
+    }
+    if written {
+      PrintInfo("Removed %s from %s", Bold(cArgs[1]), Bold(path))
+    }
+    return nil
+  }
+
+  return fmt.Errorf("Unknown action '%s'", cArgs[0])
+}
+
+/**
+ * Prints a configuration value, as a `config_value` event with the JSON output
+ */
+func (p *PluginWheelsConfigCmdConfig) printValue(key string, value string, withKey bool) {
+  if IsJSONOutput() {
+    PrintEvent("info", "config_value", map[string]interface{}{"key": key, "value": value}, "")
+  } else if withKey {
+    fmt.Printf("%s = %s\n", key, value)
+  } else {
+    fmt.Println(value)
+  }
+}
+
+/**
+ * Updates a key of the project or of the user configuration through the
+ * sandbox, and returns false if the file was not written because of dry-run
+ */
+func (p *PluginWheelsConfigCmdConfig) updateConfig(project *ProjectSandbox, path string, user bool, key string, value *string) (bool, error) {
+  var content []byte
+  var err error
+  if user {
+    content, err = ioutil.ReadFile(path)
+  } else {
+    content, err = project.ReadFile(ProjectConfigFile)
+  }
+  if err != nil && !os.IsNotExist(err) {
+    return false, fmt.Errorf("Could not read %s: %s", path, err.Error())
+  }
+
+  content, err = UpdateWheelsConfig(content, key, value)
+  if err != nil {
+    return false, err
+  }
+
+  if user {
+    err = project.WriteExternalFile(path, content)
+  } else {
+    err = project.WriteFile(ProjectConfigFile, content)
+  }
+  return err == nil && !project.IsDryRun(), err
+}
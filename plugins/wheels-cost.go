@@ -0,0 +1,353 @@
+package plugins
+
+import (
+  "flag"
+  "fmt"
+  "strings"
+  "time"
+
+  "github.com/gobwas/glob"
+  . "github.com/logrusorgru/aurora"
+  . "github.com/mesosphere-incubator/terraform-wheels/utils"
+)
+
+// The roles of the instances of an AWS cluster
+var awsClusterRoles []string = []string{"bootstrap", "masters", "private_agents", "public_agents"}
+
+// The defaults of the dcos-terraform AWS module, used for the attributes
+// that are not given when the variables of the module cannot be loaded
+var awsModuleDefaults map[string]interface{} = map[string]interface{}{
+  "num_masters":                     3,
+  "num_private_agents":              2,
+  "num_public_agents":               1,
+  "bootstrap_instance_type":         "m5.large",
+  "masters_instance_type":           "m5.xlarge",
+  "private_agents_instance_type":    "m5.xlarge",
+  "public_agents_instance_type":     "m5.xlarge",
+  "bootstrap_root_volume_size":      80,
+  "masters_root_volume_size":        120,
+  "private_agents_root_volume_size": 120,
+  "public_agents_root_volume_size":  120,
+}
+
+type costItem struct {
+  Role         string
+  Count        int
+  InstanceType string
+  Hourly       float64 // Of all the instances, together with their volumes
+}
+
+type clusterCost struct {
+  Module     string
+  Region     string
+  Items      []costItem
+  Hourly     float64
+  Expiration time.Duration // Zero if the cluster does not expire
+}
+
+/**
+ * Returns the cost until the cluster expires, or zero if it does not expire
+ */
+func (c *clusterCost) GetTotal() float64 {
+  return c.Hourly * c.Expiration.Hours()
+}
+
+type PluginWheelsCost struct {
+}
+
+func CreatePluginWheelsCost() *PluginWheelsCost {
+  return &PluginWheelsCost{}
+}
+
+func (p *PluginWheelsCost) GetName() string {
+  return "wheels-cost"
+}
+
+func (p *PluginWheelsCost) GetDependencies() []string {
+  return []string{}
+}
+
+func (p *PluginWheelsCost) GetPriority() int {
+  return 0
+}
+
+func (p *PluginWheelsCost) IsUsed(project *ProjectSandbox) (bool, error) {
+  return false, nil
+}
+
+func (p *PluginWheelsCost) BeforeRun(project *ProjectSandbox, tf *TerraformWrapper, initRun bool) error {
+  return nil
+}
+
+func (p *PluginWheelsCost) AfterRun(project *ProjectSandbox, tf *TerraformWrapper, tfErr error) error {
+  return nil
+}
+
+func (p *PluginWheelsCost) GetCommands() []PluginCommand {
+  return []PluginCommand{
+    &PluginWheelsCostCmdCost{},
+  }
+}
+
+type PluginWheelsCostCmdCost struct {
+}
+
+func (p *PluginWheelsCostCmdCost) GetName() string {
+  return "wheels-cost"
+}
+
+func (p *PluginWheelsCostCmdCost) GetDescription() string {
+  return "Estimates how much the AWS clusters of the project cost"
+}
+
+func (p *PluginWheelsCostCmdCost) Handle(args []string, project *ProjectSandbox, tf *TerraformWrapper) error {
+  fSet := flag.NewFlagSet(p.GetName(), flag.ContinueOnError)
+  fVarFile := fSet.String("var-file", "", "The tfvars file with the values of the variables (default terraform.tfvars)")
+  help := fSet.Bool("help", false, "Show this help message")
+  fSet.BoolVar(help, "h", false, "Show this help message")
+  err := fSet.Parse(args)
+  if err != nil {
+    return err
+  }
+
+  if *help {
+    PrintHelp(p.GetName(), "", []interface{}{
+      "This command will estimate the on-demand cost of the instances and the volumes",
+      "of the AWS clusters in the project, per hour, per day and until the clusters",
+      "expire. The prices can be updated in ~/.config/terraform-wheels/" + PricingFile,
+      "or in .wheels/" + PricingFile + " of the project.",
+    }, fSet)
+    return nil
+  }
+
+  costs, err := estimateAWSClusterCosts(project, *fVarFile)
+  if err != nil {
+    return err
+  }
+  if len(costs) == 0 {
+    return fmt.Errorf("Could not find an AWS cluster module in the project. Use `add-aws-cluster` to create one")
+  }
+
+  for _, cost := range costs {
+    printClusterCost(cost)
+  }
+  return nil
+}
+
+func printClusterCost(cost *clusterCost) {
+  if IsJSONOutput() {
+    var items []map[string]interface{}
+    for _, item := range cost.Items {
+      items = append(items, map[string]interface{}{
+        "role":          item.Role,
+        "count":         item.Count,
+        "instance_type": item.InstanceType,
+        "hourly":        item.Hourly,
+      })
+    }
+    PrintEvent("info", "cost_estimate", map[string]interface{}{
+      "module":           cost.Module,
+      "region":           cost.Region,
+      "items":            items,
+      "hourly":           cost.Hourly,
+      "daily":            cost.Hourly * 24,
+      "expiration_hours": cost.Expiration.Hours(),
+      "total":            cost.GetTotal(),
+    }, "")
+    return
+  }
+
+  lines := []interface{}{
+    Bold(fmt.Sprintf("Cluster %s in %s", cost.Module, cost.Region)),
+    fmt.Sprintf("  %-15s %5s  %-13s %10s", "ROLE", "COUNT", "INSTANCE", "HOURLY"),
+  }
+  for _, item := range cost.Items {
+    lines = append(lines, fmt.Sprintf("  %-15s %5d  %-13s %10s", item.Role, item.Count, item.InstanceType, formatUSD(item.Hourly)))
+  }
+  lines = append(lines,
+    "",
+    fmt.Sprintf("  %s per hour, %s per day", Bold(formatUSD(cost.Hourly)), Bold(formatUSD(cost.Hourly*24))),
+  )
+  if cost.Expiration > 0 {
+    lines = append(lines, fmt.Sprintf("  %s until the cluster expires (in %s)", Bold(formatUSD(cost.GetTotal())), cost.Expiration))
+  } else {
+    lines = append(lines, "  The cluster has no expiration tag, so it runs until it is destroyed")
+  }
+  lines = append(lines, "")
+  PrintMessage(lines)
+}
+
+func formatUSD(value float64) string {
+  return fmt.Sprintf("$%.2f", value)
+}
+
+/**
+ * Estimates the cost of all the AWS cluster modules of the project, using
+ * the values of the given tfvars files for the variables
+ */
+func estimateAWSClusterCosts(project *ProjectSandbox, varFiles ...string) ([]*clusterCost, error) {
+  pricing, err := LoadAWSPricing(project)
+  if err != nil {
+    return nil, err
+  }
+  variables, err := readVariableValues(project, varFiles...)
+  if err != nil {
+    return nil, err
+  }
+
+  var costs []*clusterCost
+  var errs []string
+  g := glob.MustCompile(dcosModuleSources["aws"])
+  for _, mod := range getDcosClusterModules(project) {
+    if source, _ := mod["source"].(string); !g.Match(source) {
+      continue
+    }
+    cost, err := estimateAWSClusterCost(project, pricing, variables, mod)
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not estimate the cost of %s: %s", mod["_name"], err.Error()))
+      continue
+    }
+    costs = append(costs, cost)
+  }
+
+  if len(errs) > 0 {
+    return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+  }
+  return costs, nil
+}
+
+func estimateAWSClusterCost(project *ProjectSandbox, pricing *PricingTable, variables map[string]interface{}, mod map[string]interface{}) (*clusterCost, error) {
+  cost := &clusterCost{Module: mod["_name"].(string)}
+  cost.Region = getModuleRegion(project, mod, variables)
+
+  defaults := getAWSModuleDefaults(project, mod)
+  value := func(name string) interface{} {
+    if v, ok := mod[name]; ok {
+      return resolveVariable(v, variables)
+    }
+    if v, ok := defaults[name]; ok && v != nil && v != "" {
+      return v
+    }
+    return awsModuleDefaults[name]
+  }
+
+  var errs []string
+  for _, role := range awsClusterRoles {
+    count := 1
+    if role != "bootstrap" {
+      n, err := hclInt(value("num_" + role))
+      if err != nil {
+        errs = append(errs, fmt.Sprintf("num_%s: %s", role, err.Error()))
+        continue
+      }
+      count = n
+    }
+    if count == 0 {
+      continue
+    }
+
+    item := costItem{Role: role, Count: count}
+    item.InstanceType = fmt.Sprintf("%v", value(role+"_instance_type"))
+    instancePrice, err := pricing.GetInstancePrice(cost.Region, item.InstanceType)
+    if err != nil {
+      errs = append(errs, err.Error())
+      continue
+    }
+
+    // The root volume and the extra volumes of every instance
+    volumes := []map[string]interface{}{{
+      "size": value(role + "_root_volume_size"),
+      "type": value(role + "_root_volume_type"),
+    }}
+    volumes = append(volumes, hclMaps(value(role+"_extra_volumes"))...)
+
+    volumesPrice := 0.0
+    for _, volume := range volumes {
+      size, err := hclInt(resolveVariable(volume["size"], variables))
+      if err != nil {
+        errs = append(errs, fmt.Sprintf("%s volume size: %s", role, err.Error()))
+        continue
+      }
+      volumeType := "gp2"
+      if t, ok := resolveVariable(volume["type"], variables).(string); ok && t != "" {
+        volumeType = t
+      }
+      price, err := pricing.GetVolumePrice(cost.Region, volumeType)
+      if err != nil {
+        errs = append(errs, err.Error())
+        continue
+      }
+      volumesPrice += price * float64(size)
+    }
+
+    item.Hourly = (instancePrice + volumesPrice) * float64(count)
+    cost.Hourly += item.Hourly
+    cost.Items = append(cost.Items, item)
+  }
+  if len(errs) > 0 {
+    return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+  }
+
+  for _, tags := range hclMaps(value("tags")) {
+    if expiration, ok := resolveVariable(tags["expiration"], variables).(string); ok {
+      duration, err := ParseExpiration(expiration)
+      if err != nil {
+        return nil, err
+      }
+      cost.Expiration = duration
+    }
+  }
+
+  return cost, nil
+}
+
+/**
+ * Returns the default values of the variables of the module version that the
+ * cluster uses, or nil if they cannot be loaded
+ */
+func getAWSModuleDefaults(project *ProjectSandbox, mod map[string]interface{}) map[string]interface{} {
+  version, _ := mod["version"].(string)
+  version = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(version), "~>"))
+  if version == "" {
+    return nil
+  }
+
+  moduleVars, err := LoadModuleVariables(project, "aws", version)
+  if err != nil {
+    return nil
+  }
+
+  defaults := make(map[string]interface{})
+  for _, v := range moduleVars {
+    defaults[v.Name] = v.Default
+  }
+  return defaults
+}
+
+/**
+ * Returns the region of the AWS provider that the module uses, looking for
+ * the provider blocks in the file of the module
+ */
+func getModuleRegion(project *ProjectSandbox, mod map[string]interface{}, variables map[string]interface{}) string {
+  alias := ""
+  for _, providers := range hclMaps(mod["providers"]) {
+    if ref, ok := providers["aws"].(string); ok && strings.HasPrefix(ref, "aws.") {
+      alias = strings.TrimPrefix(ref, "aws.")
+    }
+  }
+
+  if file, ok := mod["_file"].(string); ok {
+    if content, err := project.ReadTerraformFile(file); err == nil {
+      for _, provider := range hclMaps(content["provider"]) {
+        for _, block := range hclMaps(provider["aws"]) {
+          blockAlias, _ := block["alias"].(string)
+          if region, ok := resolveVariable(block["region"], variables).(string); ok && blockAlias == alias {
+            return region
+          }
+        }
+      }
+    }
+  }
+
+  return project.GetConfig().GetRegion()
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+  "flag"
+  "fmt"
+  "os"
+  "strings"
+
+  . "github.com/mesosphere-incubator/terraform-wheels/plugins"
+)
+
+type CommandInfo struct {
+  Name        string
+  Description string
+}
+
+// The terraform commands we pass through
+var terraformCommands []CommandInfo = []CommandInfo{
+  {"apply", "Builds or changes infrastructure"},
+  {"console", "Interactive console for Terraform interpolations"},
+  {"destroy", "Destroy Terraform-managed infrastructure"},
+  {"env", "Workspace management"},
+  {"fmt", "Rewrites config files to canonical format"},
+  {"get", "Download and install modules for the configuration"},
+  {"graph", "Create a visual graph of Terraform resources"},
+  {"import", "Import existing infrastructure into Terraform"},
+  {"init", "Initialize a Terraform working directory"},
+  {"output", "Read an output from a state file"},
+  {"plan", "Generate and show an execution plan"},
+  {"providers", "Prints a tree of the providers used in the configuration"},
+  {"push", "Upload this Terraform module to Atlas to run"},
+  {"refresh", "Update local state file against real resources"},
+  {"show", "Inspect Terraform state or plan"},
+  {"taint", "Manually mark a resource for recreation"},
+  {"untaint", "Manually unmark a resource as tainted"},
+  {"validate", "Validates the Terraform files"},
+  {"version", "Prints the Terraform version"},
+  {"workspace", "Workspace management"},
+  {"0.12checklist", "Checks whether the configuration is ready for Terraform v0.12"},
+  {"debug", "Debug output management (experimental)"},
+  {"force-unlock", "Manually unlock the terraform state"},
+  {"state", "Advanced state management"},
+}
+
+// The sub-commands of the terraform commands that have them
+var terraformSubCommands map[string][]string = map[string][]string{
+  "state":     []string{"list", "mv", "pull", "push", "rm", "show"},
+  "workspace": []string{"delete", "list", "new", "select", "show"},
+  "env":       []string{"delete", "list", "new", "select"},
+  "debug":     []string{"json2dot"},
+}
+
+// The commands handled by terraform-wheels itself
+var builtinCommands []CommandInfo = []CommandInfo{
+  {"help", "Show the help page of a command"},
+  {"wheels-version", "Check the version of terraform-wheels"},
+  {"wheels-upgrade", "Upgrade to the latest version of terraform-wheels"},
+  {"wheels-completion", "Print the shell completion script (bash, zsh or fish)"},
+}
+
+// The plugin commands that must work even if the project or the environment
+// is broken, since they are used to diagnose it
+var diagnosticCommands []string = []string{"wheels-doctor"}
+
+func IsDiagnosticCommand(name string) bool {
+  for _, cmd := range diagnosticCommands {
+    if cmd == name {
+      return true
+    }
+  }
+  return false
+}
+
+// The global terraform-wheels flags. They can be given anywhere in the
+// command-line with the `-wheels-` or `--wheels-` prefix and they are never
+// passed to terraform.
+type WheelsOptions struct {
+  Chdir  string
+  DryRun bool
+  Backup bool
+  Output string
+}
+
+var wheelsOptions WheelsOptions
+
+func createWheelsFlags(opts *WheelsOptions) *flag.FlagSet {
+  fSet := flag.NewFlagSet("terraform-wheels", flag.ContinueOnError)
+  fSet.StringVar(&opts.Chdir, "wheels-chdir", "", "Use the given directory as the project directory")
+  fSet.BoolVar(&opts.DryRun, "wheels-dry-run", false, "Show the changes to the project files instead of writing them")
+  fSet.BoolVar(&opts.Backup, "wheels-backup", false, "Keep a timestamped copy of every overwritten file in .wheels/backups")
+  fSet.StringVar(&opts.Output, "wheels-output", os.Getenv("WHEELS_OUTPUT"), "The output format: text, json or json-passthrough")
+  return fSet
+}
+
+type CommandLine struct {
+  // The first argument that is not a flag, or empty if there is none
+  Command string
+
+  // The arguments that follow the command
+  Args []string
+
+  // The arguments to pass to terraform, without the wheels flags
+  TerraformArgs []string
+}
+
+/**
+ * Splits the given command-line (without the program name) into the global
+ * wheels flags, that are parsed into `wheelsOptions`, and the command
+ */
+func ParseCommandLine(args []string) (*CommandLine, error) {
+  fSet := createWheelsFlags(&wheelsOptions)
+  fSet.Usage = func() {}
+
+  var wheelsArgs []string
+  var otherArgs []string
+  for i := 0; i < len(args); i++ {
+    arg := args[i]
+    name := strings.TrimLeft(arg, "-")
+    if !strings.HasPrefix(arg, "-") || !strings.HasPrefix(name, "wheels-") {
+      otherArgs = append(otherArgs, arg)
+      continue
+    }
+
+    wheelsArgs = append(wheelsArgs, "-"+name)
+
+    // Non-boolean flags without `=` take the next argument as value
+    if !strings.Contains(name, "=") {
+      f := fSet.Lookup(name)
+      if f == nil {
+        return nil, fmt.Errorf("Unknown flag: %s", arg)
+      }
+      if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); (!ok || !bf.IsBoolFlag()) && i+1 < len(args) {
+        i++
+        wheelsArgs = append(wheelsArgs, args[i])
+      }
+    }
+  }
+
+  err := fSet.Parse(wheelsArgs)
+  if err != nil {
+    return nil, err
+  }
+
+  cmdline := &CommandLine{"", nil, otherArgs}
+  for i, arg := range otherArgs {
+    if !strings.HasPrefix(arg, "-") {
+      cmdline.Command = arg
+      cmdline.Args = otherArgs[i+1:]
+      break
+    }
+  }
+
+  return cmdline, nil
+}
+
+/**
+ * Checks if the command-line asks for the help page, either with no command
+ * at all, or with a help flag and no command
+ */
+func (c *CommandLine) IsHelp() bool {
+  if c.Command == "help" {
+    return true
+  }
+  if c.Command != "" {
+    return false
+  }
+
+  for _, arg := range c.TerraformArgs {
+    if arg == "-h" || arg == "-help" || arg == "--help" {
+      return true
+    }
+  }
+
+  // Only version flags are meaningful for terraform without a command
+  for _, arg := range c.TerraformArgs {
+    if arg == "-v" || arg == "-version" || arg == "--version" {
+      return false
+    }
+  }
+
+  return true
+}
+
+func IsTerraformCommand(name string) bool {
+  for _, cmd := range terraformCommands {
+    if cmd.Name == name {
+      return true
+    }
+  }
+  return false
+}
+
+func FindPluginCommand(plugins []Plugin, name string) PluginCommand {
+  for _, plugin := range plugins {
+    for _, cmd := range plugin.GetCommands() {
+      if cmd.GetName() == name {
+        return cmd
+      }
+    }
+  }
+  return nil
+}
+
+func FindCommandPlugin(plugins []Plugin, name string) Plugin {
+  for _, plugin := range plugins {
+    for _, cmd := range plugin.GetCommands() {
+      if cmd.GetName() == name {
+        return plugin
+      }
+    }
+  }
+  return nil
+}
+
+/**
+ * Returns all the commands known to terraform-wheels, including the terraform
+ * commands passed through
+ */
+func GetAllCommands(plugins []Plugin) []CommandInfo {
+  var ret []CommandInfo = nil
+  for _, cmd := range builtinCommands {
+    ret = append(ret, cmd)
+  }
+  for _, plugin := range plugins {
+    for _, cmd := range plugin.GetCommands() {
+      ret = append(ret, CommandInfo{cmd.GetName(), cmd.GetDescription()})
+    }
+  }
+  ret = append(ret, terraformCommands...)
+  return ret
+}
@@ -1,7 +1,13 @@
 package utils
 
 import (
+  "fmt"
+
+  "github.com/aws/aws-sdk-go/aws"
+  "github.com/aws/aws-sdk-go/aws/awserr"
   "github.com/aws/aws-sdk-go/aws/session"
+  "github.com/aws/aws-sdk-go/service/dynamodb"
+  "github.com/aws/aws-sdk-go/service/s3"
   "github.com/aws/aws-sdk-go/service/sts"
 )
 
@@ -16,3 +22,124 @@ func IsAWSCredsOK() bool {
 
   return true
 }
+
+func newAWSSession(region string) (*session.Session, error) {
+  sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+  if err != nil {
+    return nil, fmt.Errorf("Could not create AWS session: %s", err.Error())
+  }
+  return sess, nil
+}
+
+/**
+ * Creates a private, versioned and encrypted S3 bucket for the terraform
+ * state, if it does not exist already
+ */
+func CreateS3StateBucket(region string, bucket string) (bool, error) {
+  sess, err := newAWSSession(region)
+  if err != nil {
+    return false, err
+  }
+  svc := s3.New(sess)
+
+  _, err = svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(bucket)})
+  if err == nil {
+    return false, nil
+  }
+  if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "NotFound" {
+    return false, fmt.Errorf("Could not check bucket %s: %s", bucket, err.Error())
+  }
+
+  input := &s3.CreateBucketInput{Bucket: aws.String(bucket)}
+  if region != "us-east-1" {
+    input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+      LocationConstraint: aws.String(region),
+    }
+  }
+  _, err = svc.CreateBucket(input)
+  if err != nil {
+    return false, fmt.Errorf("Could not create bucket %s: %s", bucket, err.Error())
+  }
+
+  _, err = svc.PutBucketVersioning(&s3.PutBucketVersioningInput{
+    Bucket: aws.String(bucket),
+    VersioningConfiguration: &s3.VersioningConfiguration{
+      Status: aws.String(s3.BucketVersioningStatusEnabled),
+    },
+  })
+  if err != nil {
+    return true, fmt.Errorf("Could not enable versioning on bucket %s: %s", bucket, err.Error())
+  }
+
+  _, err = svc.PutBucketEncryption(&s3.PutBucketEncryptionInput{
+    Bucket: aws.String(bucket),
+    ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+      Rules: []*s3.ServerSideEncryptionRule{{
+        ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+          SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+        },
+      }},
+    },
+  })
+  if err != nil {
+    return true, fmt.Errorf("Could not enable encryption on bucket %s: %s", bucket, err.Error())
+  }
+
+  _, err = svc.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+    Bucket: aws.String(bucket),
+    PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+      BlockPublicAcls:       aws.Bool(true),
+      BlockPublicPolicy:     aws.Bool(true),
+      IgnorePublicAcls:      aws.Bool(true),
+      RestrictPublicBuckets: aws.Bool(true),
+    },
+  })
+  if err != nil {
+    return true, fmt.Errorf("Could not block public access to bucket %s: %s", bucket, err.Error())
+  }
+
+  return true, nil
+}
+
+/**
+ * Creates the DynamoDB table terraform uses for locking the state, if it does
+ * not exist already
+ */
+func CreateDynamoDBLockTable(region string, table string) (bool, error) {
+  sess, err := newAWSSession(region)
+  if err != nil {
+    return false, err
+  }
+  svc := dynamodb.New(sess)
+
+  _, err = svc.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(table)})
+  if err == nil {
+    return false, nil
+  }
+  if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != dynamodb.ErrCodeResourceNotFoundException {
+    return false, fmt.Errorf("Could not check table %s: %s", table, err.Error())
+  }
+
+  // Terraform expects a `LockID` string hash key
+  _, err = svc.CreateTable(&dynamodb.CreateTableInput{
+    TableName:   aws.String(table),
+    BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+    AttributeDefinitions: []*dynamodb.AttributeDefinition{{
+      AttributeName: aws.String("LockID"),
+      AttributeType: aws.String(dynamodb.ScalarAttributeTypeS),
+    }},
+    KeySchema: []*dynamodb.KeySchemaElement{{
+      AttributeName: aws.String("LockID"),
+      KeyType:       aws.String(dynamodb.KeyTypeHash),
+    }},
+  })
+  if err != nil {
+    return false, fmt.Errorf("Could not create table %s: %s", table, err.Error())
+  }
+
+  err = svc.WaitUntilTableExists(&dynamodb.DescribeTableInput{TableName: aws.String(table)})
+  if err != nil {
+    return true, fmt.Errorf("Could not wait for table %s: %s", table, err.Error())
+  }
+  return true, nil
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+  "bytes"
+  "encoding/json"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+)
+
+type azureProfile struct {
+  Subscriptions []struct {
+    ID        string `json:"id"`
+    IsDefault bool   `json:"isDefault"`
+  } `json:"subscriptions"`
+}
+
+func getAzureConfigDir() string {
+  if dir, ok := os.LookupEnv("AZURE_CONFIG_DIR"); ok {
+    return dir
+  }
+  home, err := os.UserHomeDir()
+  if err != nil {
+    return ""
+  }
+  return filepath.Join(home, ".azure")
+}
+
+/**
+ * Checks if there are Azure credentials, either given to terraform with the
+ * ARM_* environment variables of a service principal, or from a `az login`
+ * session with a default subscription
+ */
+func IsAzureCredsOK() bool {
+  if os.Getenv("ARM_CLIENT_ID") != "" && os.Getenv("ARM_CLIENT_SECRET") != "" &&
+    os.Getenv("ARM_SUBSCRIPTION_ID") != "" && os.Getenv("ARM_TENANT_ID") != "" {
+    return true
+  }
+
+  return GetAzureSubscriptionID() != ""
+}
+
+/**
+ * Returns the subscription ID to use, or an empty string if it could not
+ * be found
+ */
+func GetAzureSubscriptionID() string {
+  if id := os.Getenv("ARM_SUBSCRIPTION_ID"); id != "" {
+    return id
+  }
+
+  dir := getAzureConfigDir()
+  if dir == "" {
+    return ""
+  }
+  content, err := ioutil.ReadFile(filepath.Join(dir, "azureProfile.json"))
+  if err != nil {
+    return ""
+  }
+
+  // The Azure CLI writes the file with a BOM
+  var profile azureProfile
+  err = json.Unmarshal(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")), &profile)
+  if err != nil {
+    return ""
+  }
+
+  for _, sub := range profile.Subscriptions {
+    if sub.IsDefault {
+      return sub.ID
+    }
+  }
+  return ""
+}
@@ -0,0 +1,432 @@
+package utils
+
+import (
+  "bytes"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "os/user"
+  "path/filepath"
+  "sort"
+  "strconv"
+  "strings"
+
+  "github.com/imdario/mergo"
+  "gopkg.in/yaml.v3"
+)
+
+// The name of the per-project configuration file
+var ProjectConfigFile string = ".wheels.yaml"
+
+// The configuration keys that hold lists, given as comma-separated values
+var configListKeys []string = []string{"plugins.enabled", "plugins.disabled"}
+
+// The configuration keys that hold numbers
+var configNumberKeys []string = []string{"budget.hourly", "budget.total"}
+
+type WheelsPluginsConfig struct {
+  // Plugins to use even if they don't detect their resources in the project
+  Enabled []string `yaml:"enabled,omitempty"`
+
+  // Plugins to never use
+  Disabled []string `yaml:"disabled,omitempty"`
+}
+
+/**
+ * Shell snippets to run before and after a terraform command
+ */
+type WheelsHooksConfig struct {
+  Pre  []string `yaml:"pre,omitempty"`
+  Post []string `yaml:"post,omitempty"`
+}
+
+/**
+ * The maximum cost of the clusters of the project, in USD
+ */
+type WheelsBudgetConfig struct {
+  Hourly float64 `yaml:"hourly,omitempty"`
+
+  // The cost until the clusters expire
+  Total float64 `yaml:"total,omitempty"`
+}
+
+/**
+ * The terraform-wheels configuration, layered from the user and the project
+ * configuration files
+ */
+type WheelsConfig struct {
+  Region     string            `yaml:"region,omitempty"`
+  Owner      string            `yaml:"owner,omitempty"`
+  Expiration string            `yaml:"expiration,omitempty"`
+  Tags       map[string]string `yaml:"tags,omitempty"`
+
+  Plugins WheelsPluginsConfig `yaml:"plugins,omitempty"`
+
+  // The terraform version (or version prefix) to require
+  TerraformVersion string `yaml:"terraform_version,omitempty"`
+
+  // The default module version, per cloud provider (ex. `aws: 0.2.0`)
+  ModuleVersions map[string]string `yaml:"module_versions,omitempty"`
+
+  // The hooks to run, per terraform command (ex. `apply`)
+  Hooks map[string]WheelsHooksConfig `yaml:"hooks,omitempty"`
+
+  // The budget to warn about before an `apply`
+  Budget WheelsBudgetConfig `yaml:"budget,omitempty"`
+}
+
+/**
+ * Returns the directory where the user configuration is stored
+ */
+func GetUserConfigDir() (string, error) {
+  home, err := os.UserHomeDir()
+  if err != nil {
+    return "", fmt.Errorf("Could not find the home directory: %s", err.Error())
+  }
+
+  return filepath.Join(home, ".config", "terraform-wheels"), nil
+}
+
+func GetUserConfigPath() (string, error) {
+  dir, err := GetUserConfigDir()
+  if err != nil {
+    return "", err
+  }
+
+  return filepath.Join(dir, "config.yaml"), nil
+}
+
+/**
+ * Loads the user configuration and then the project configuration over it
+ */
+func LoadWheelsConfig(projectDir string) (*WheelsConfig, error) {
+  cfg := &WheelsConfig{}
+
+  var paths []string
+  if userPath, err := GetUserConfigPath(); err == nil {
+    paths = append(paths, userPath)
+  }
+  paths = append(paths, filepath.Join(projectDir, ProjectConfigFile))
+
+  for _, path := range paths {
+    layer, err := ReadWheelsConfigFile(path)
+    if err != nil {
+      return nil, err
+    }
+
+    err = mergo.Merge(cfg, layer, mergo.WithOverride)
+    if err != nil {
+      return nil, fmt.Errorf("Could not merge %s: %s", path, err.Error())
+    }
+  }
+
+  return cfg, nil
+}
+
+/**
+ * Reads a single configuration file, returning an empty configuration if the
+ * file does not exist
+ */
+func ReadWheelsConfigFile(path string) (*WheelsConfig, error) {
+  cfg := &WheelsConfig{}
+
+  content, err := ioutil.ReadFile(path)
+  if err != nil {
+    if os.IsNotExist(err) {
+      return cfg, nil
+    }
+    return nil, fmt.Errorf("Could not read %s: %s", path, err.Error())
+  }
+
+  err = decodeWheelsConfig(content, cfg)
+  if err != nil {
+    return nil, fmt.Errorf("Could not parse %s: %s", path, err.Error())
+  }
+
+  return cfg, nil
+}
+
+func decodeWheelsConfig(content []byte, cfg *WheelsConfig) error {
+  if len(bytes.TrimSpace(content)) == 0 {
+    return nil
+  }
+
+  dec := yaml.NewDecoder(bytes.NewReader(content))
+  dec.KnownFields(true)
+  return dec.Decode(cfg)
+}
+
+func (c *WheelsConfig) GetRegion() string {
+  if c.Region != "" {
+    return c.Region
+  }
+  return "us-west-2"
+}
+
+func (c *WheelsConfig) GetOwner() string {
+  if c.Owner != "" {
+    return c.Owner
+  }
+  if u, err := user.Current(); err == nil {
+    return u.Username
+  }
+  return "somebody"
+}
+
+func (c *WheelsConfig) GetExpiration() string {
+  if c.Expiration != "" {
+    return c.Expiration
+  }
+  return "1h"
+}
+
+func (c *WheelsConfig) GetTerraformVersion() string {
+  if c.TerraformVersion != "" {
+    return c.TerraformVersion
+  }
+  return RequiredTerraformVersionPrefix
+}
+
+/**
+ * Returns the module version configured for the given provider, or calls
+ * `latest` to find one
+ */
+func (c *WheelsConfig) GetModuleVersion(provider string, latest func() string) string {
+  if v, ok := c.ModuleVersions[provider]; ok && v != "" {
+    return v
+  }
+  return latest()
+}
+
+func (c *WheelsConfig) IsPluginEnabled(name string) bool {
+  for _, n := range c.Plugins.Enabled {
+    if n == name {
+      return true
+    }
+  }
+  return false
+}
+
+func (c *WheelsConfig) IsPluginDisabled(name string) bool {
+  for _, n := range c.Plugins.Disabled {
+    if n == name {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Returns the hooks of the given phase (`pre` or `post`) of a terraform command
+ */
+func (c *WheelsConfig) GetHooks(command string, phase string) []string {
+  hooks, ok := c.Hooks[command]
+  if !ok {
+    return nil
+  }
+  if phase == "pre" {
+    return hooks.Pre
+  }
+  return hooks.Post
+}
+
+/**
+ * Flattens the configuration into `key.path = value` pairs
+ */
+func (c *WheelsConfig) Flatten() (map[string]string, error) {
+  content, err := yaml.Marshal(c)
+  if err != nil {
+    return nil, err
+  }
+
+  values := make(map[string]interface{})
+  err = yaml.Unmarshal(content, &values)
+  if err != nil {
+    return nil, err
+  }
+
+  ret := make(map[string]string)
+  flattenConfigValues(values, "", ret)
+  return ret, nil
+}
+
+func flattenConfigValues(values map[string]interface{}, prefix string, ret map[string]string) {
+  for k, v := range values {
+    switch tv := v.(type) {
+    case map[string]interface{}:
+      flattenConfigValues(tv, prefix+k+".", ret)
+    case []interface{}:
+      var items []string
+      for _, item := range tv {
+        items = append(items, fmt.Sprintf("%v", item))
+      }
+      ret[prefix+k] = strings.Join(items, ",")
+    default:
+      ret[prefix+k] = fmt.Sprintf("%v", tv)
+    }
+  }
+}
+
+/**
+ * Returns the sorted keys of a flattened configuration
+ */
+func SortedConfigKeys(values map[string]string) []string {
+  var keys []string
+  for k := range values {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+  return keys
+}
+
+func isConfigListKey(key string) bool {
+  for _, k := range configListKeys {
+    if k == key {
+      return true
+    }
+  }
+  return false
+}
+
+func isConfigNumberKey(key string) bool {
+  for _, k := range configNumberKeys {
+    if k == key {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Sets (or removes if `value` is nil) the value at the given `key.path` of
+ * the contents of a configuration file (nil if there is no file yet), and
+ * returns the new contents once validated. The file is edited as a YAML node
+ * tree, so the comments and the order of the keys are kept. The caller writes
+ * the contents, so that the dry-run mode of the sandbox is honored.
+ */
+func UpdateWheelsConfig(content []byte, key string, value *string) ([]byte, error) {
+  var doc yaml.Node
+  if len(bytes.TrimSpace(content)) > 0 {
+    err := yaml.Unmarshal(content, &doc)
+    if err != nil {
+      return nil, fmt.Errorf("Could not parse the configuration: %s", err.Error())
+    }
+  }
+  if len(doc.Content) == 0 {
+    doc = yaml.Node{
+      Kind:    yaml.DocumentNode,
+      Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+    }
+  }
+  if doc.Content[0].Kind != yaml.MappingNode {
+    return nil, fmt.Errorf("Could not parse the configuration: Expected a map of keys")
+  }
+
+  // Walk to the parent map of the key, creating it if needed
+  parts := strings.Split(key, ".")
+  parent := doc.Content[0]
+  for _, part := range parts[:len(parts)-1] {
+    _, child := findConfigNode(parent, part)
+    if child == nil || child.Kind != yaml.MappingNode {
+      if value == nil {
+        return content, nil
+      }
+      if child == nil {
+        child = &yaml.Node{}
+        parent.Content = append(parent.Content, configKeyNode(part), child)
+      }
+      *child = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: child.HeadComment, LineComment: child.LineComment}
+    }
+    parent = child
+  }
+
+  name := parts[len(parts)-1]
+  idx, old := findConfigNode(parent, name)
+  if value == nil {
+    if old == nil {
+      return content, nil
+    }
+    parent.Content = append(parent.Content[:idx], parent.Content[idx+2:]...)
+  } else {
+    var newValue interface{} = *value
+    if isConfigListKey(key) {
+      var items []string
+      for _, item := range strings.Split(*value, ",") {
+        if strings.TrimSpace(item) != "" {
+          items = append(items, strings.TrimSpace(item))
+        }
+      }
+      newValue = items
+    } else if isConfigNumberKey(key) {
+      number, err := strconv.ParseFloat(*value, 64)
+      if err != nil {
+        return nil, fmt.Errorf("Invalid value for '%s': Expected a number", key)
+      }
+      newValue = number
+    }
+
+    node, err := configValueNode(newValue)
+    if err != nil {
+      return nil, fmt.Errorf("Could not encode configuration: %s", err.Error())
+    }
+    if old == nil {
+      parent.Content = append(parent.Content, configKeyNode(name), node)
+    } else {
+      node.LineComment = old.LineComment
+      *old = *node
+    }
+  }
+
+  var buf bytes.Buffer
+  enc := yaml.NewEncoder(&buf)
+  enc.SetIndent(2)
+  err := enc.Encode(&doc)
+  if err != nil {
+    return nil, fmt.Errorf("Could not encode configuration: %s", err.Error())
+  }
+  enc.Close()
+  content = buf.Bytes()
+
+  // Make sure the result is still a valid configuration
+  err = decodeWheelsConfig(content, &WheelsConfig{})
+  if err != nil {
+    return nil, fmt.Errorf("Invalid configuration key '%s': %s", key, err.Error())
+  }
+
+  return content, nil
+}
+
+/**
+ * Returns the index of the key and the value node of the given key of a YAML
+ * map, or a nil value if the key is not present
+ */
+func findConfigNode(mapping *yaml.Node, key string) (int, *yaml.Node) {
+  for i := 0; i+1 < len(mapping.Content); i += 2 {
+    if mapping.Content[i].Value == key {
+      return i, mapping.Content[i+1]
+    }
+  }
+  return -1, nil
+}
+
+func configKeyNode(key string) *yaml.Node {
+  return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
+
+/**
+ * Encodes a value as a YAML node, so it's quoted or typed like yaml.Marshal
+ * would do it
+ */
+func configValueNode(value interface{}) (*yaml.Node, error) {
+  content, err := yaml.Marshal(value)
+  if err != nil {
+    return nil, err
+  }
+
+  var doc yaml.Node
+  err = yaml.Unmarshal(content, &doc)
+  if err != nil {
+    return nil, err
+  }
+  return doc.Content[0], nil
+}
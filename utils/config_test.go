@@ -0,0 +1,97 @@
+package utils
+
+import (
+  "testing"
+)
+
+func TestUpdateWheelsConfig(t *testing.T) {
+  input := `# The defaults of the team
+region: eu-central-1 # closest to us
+owner: jdoe
+tags:
+  team: infrastructure
+`
+
+  tests := []struct {
+    key      string
+    value    *string
+    expected string
+  }{
+    {
+      "region",
+      stringPtr("us-east-1"),
+      `# The defaults of the team
+region: us-east-1 # closest to us
+owner: jdoe
+tags:
+  team: infrastructure
+`,
+    },
+    {
+      "tags.cost-center",
+      stringPtr("42"),
+      `# The defaults of the team
+region: eu-central-1 # closest to us
+owner: jdoe
+tags:
+  team: infrastructure
+  cost-center: "42"
+`,
+    },
+    {
+      "plugins.disabled",
+      stringPtr("ssh-agent, dcos-provider"),
+      `# The defaults of the team
+region: eu-central-1 # closest to us
+owner: jdoe
+tags:
+  team: infrastructure
+plugins:
+  disabled:
+  - ssh-agent
+  - dcos-provider
+`,
+    },
+    {
+      "owner",
+      nil,
+      `# The defaults of the team
+region: eu-central-1 # closest to us
+tags:
+  team: infrastructure
+`,
+    },
+    {
+      "budget.hourly",
+      nil,
+      input,
+    },
+  }
+
+  for _, test := range tests {
+    content, err := UpdateWheelsConfig([]byte(input), test.key, test.value)
+    if err != nil {
+      t.Errorf("%s: unexpected error: %s", test.key, err.Error())
+      continue
+    }
+    if string(content) != test.expected {
+      t.Errorf("%s: expected:\n%s\ngot:\n%s", test.key, test.expected, string(content))
+    }
+  }
+}
+
+func TestUpdateWheelsConfigInvalid(t *testing.T) {
+  _, err := UpdateWheelsConfig(nil, "budget.hourly", stringPtr("a lot"))
+  if err == nil {
+    t.Errorf("expected an error for a number key")
+  }
+
+  _, err = UpdateWheelsConfig(nil, "unknown", stringPtr("value"))
+  if err == nil {
+    t.Errorf("expected an error for an unknown key")
+  }
+}
+
+func stringPtr(s string) *string {
+  return &s
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
   "encoding/json"
+  "fmt"
+
   "github.com/Masterminds/semver/v3"
 )
 
@@ -46,7 +48,16 @@ func GetLatestDCOSVersion(variant string, defaultVersion string) string {
 }
 
 func GetLatestModuleVersion(defaultVersion string) string {
-  buf, err := Download("https://api.github.com/repos/dcos-terraform/terraform-aws-dcos/releases", WithDefaults).EventuallyReadAll()
+  return GetLatestProviderModuleVersion("aws", defaultVersion)
+}
+
+/**
+ * Returns the latest released version of the dcos-terraform module of the
+ * given cloud provider (ex. `aws`, `azurerm` or `gcp`)
+ */
+func GetLatestProviderModuleVersion(provider string, defaultVersion string) string {
+  url := fmt.Sprintf("https://api.github.com/repos/dcos-terraform/terraform-%s-dcos/releases", provider)
+  buf, err := Download(url, WithDefaults).EventuallyReadAll()
   if err != nil {
     return defaultVersion
   }
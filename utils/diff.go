@@ -0,0 +1,172 @@
+package utils
+
+import (
+  "fmt"
+  "strings"
+
+  . "github.com/logrusorgru/aurora"
+)
+
+type diffOp struct {
+  kind byte // ' ', '-' or '+'
+  line string
+}
+
+func splitDiffLines(content []byte) []string {
+  if len(content) == 0 {
+    return nil
+  }
+  return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+/**
+ * Computes the line operations that transform `a` into `b` using the
+ * longest common subsequence of their lines
+ */
+func diffLines(a []string, b []string) []diffOp {
+  lcs := make([][]int, len(a)+1)
+  for i := range lcs {
+    lcs[i] = make([]int, len(b)+1)
+  }
+  for i := len(a) - 1; i >= 0; i-- {
+    for j := len(b) - 1; j >= 0; j-- {
+      if a[i] == b[j] {
+        lcs[i][j] = lcs[i+1][j+1] + 1
+      } else if lcs[i+1][j] >= lcs[i][j+1] {
+        lcs[i][j] = lcs[i+1][j]
+      } else {
+        lcs[i][j] = lcs[i][j+1]
+      }
+    }
+  }
+
+  var ops []diffOp
+  i, j := 0, 0
+  for i < len(a) && j < len(b) {
+    if a[i] == b[j] {
+      ops = append(ops, diffOp{' ', a[i]})
+      i++
+      j++
+    } else if lcs[i+1][j] >= lcs[i][j+1] {
+      ops = append(ops, diffOp{'-', a[i]})
+      i++
+    } else {
+      ops = append(ops, diffOp{'+', b[j]})
+      j++
+    }
+  }
+  for ; i < len(a); i++ {
+    ops = append(ops, diffOp{'-', a[i]})
+  }
+  for ; j < len(b); j++ {
+    ops = append(ops, diffOp{'+', b[j]})
+  }
+
+  return ops
+}
+
+/**
+ * Returns the unified diff between the two contents, with the given number
+ * of context lines, or an empty string if they are the same
+ */
+func UnifiedDiff(aName string, bName string, a []byte, b []byte, context int) string {
+  ops := diffLines(splitDiffLines(a), splitDiffLines(b))
+
+  var lines []string
+  for start := 0; start < len(ops); {
+    // Find the next change
+    for start < len(ops) && ops[start].kind == ' ' {
+      start++
+    }
+    if start == len(ops) {
+      break
+    }
+
+    // Extend the hunk until there are more than 2*context unchanged lines
+    end := start
+    for end < len(ops) {
+      if ops[end].kind != ' ' {
+        end++
+        continue
+      }
+      next := end
+      for next < len(ops) && ops[next].kind == ' ' {
+        next++
+      }
+      if next == len(ops) || next-end > 2*context {
+        break
+      }
+      end = next
+    }
+
+    hunkStart := start - context
+    if hunkStart < 0 {
+      hunkStart = 0
+    }
+    hunkEnd := end + context
+    if hunkEnd > len(ops) {
+      hunkEnd = len(ops)
+    }
+
+    // Compute the line numbers of the hunk
+    aLine, bLine := 1, 1
+    for _, op := range ops[:hunkStart] {
+      if op.kind != '+' {
+        aLine++
+      }
+      if op.kind != '-' {
+        bLine++
+      }
+    }
+    aCount, bCount := 0, 0
+    var hunk []string
+    for _, op := range ops[hunkStart:hunkEnd] {
+      if op.kind != '+' {
+        aCount++
+      }
+      if op.kind != '-' {
+        bCount++
+      }
+      hunk = append(hunk, string(op.kind)+op.line)
+    }
+    if aCount == 0 {
+      aLine--
+    }
+    if bCount == 0 {
+      bLine--
+    }
+
+    lines = append(lines, fmt.Sprintf("@@ -%d,%d +%d,%d @@", aLine, aCount, bLine, bCount))
+    lines = append(lines, hunk...)
+    start = hunkEnd
+  }
+
+  if len(lines) == 0 {
+    return ""
+  }
+
+  header := []string{
+    fmt.Sprintf("--- %s", aName),
+    fmt.Sprintf("+++ %s", bName),
+  }
+  return strings.Join(append(header, lines...), "\n") + "\n"
+}
+
+/**
+ * Prints a unified diff with colors
+ */
+func PrintDiff(diff string) {
+  for _, line := range splitDiffLines([]byte(diff)) {
+    var out interface{} = line
+    if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+      out = Bold(line)
+    } else if strings.HasPrefix(line, "@@") {
+      out = Cyan(line)
+    } else if strings.HasPrefix(line, "+") {
+      out = Green(line)
+    } else if strings.HasPrefix(line, "-") {
+      out = Red(line)
+    }
+    colorableStdout.Write([]byte(fmt.Sprintf("%s\n", out)))
+  }
+}
@@ -0,0 +1,183 @@
+package utils
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "io"
+  "regexp"
+  "sync"
+  "time"
+
+  . "github.com/logrusorgru/aurora"
+)
+
+const (
+  // Human-readable, colored output
+  OutputText = "text"
+
+  // Every message is a JSON event, including the output of terraform
+  OutputJSON = "json"
+
+  // Every message is a JSON event, but terraform output is passed through
+  OutputJSONPassthrough = "json-passthrough"
+)
+
+var outputFormat string = OutputText
+var eventPlugin string
+var eventLock sync.Mutex
+
+var ansiEscapeRe = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+/**
+ * Selects how messages are printed
+ */
+func SetOutputFormat(format string) error {
+  switch format {
+  case "", OutputText:
+    outputFormat = OutputText
+  case OutputJSON, OutputJSONPassthrough:
+    outputFormat = format
+  default:
+    return fmt.Errorf("Unknown output format '%s', please use one of: %s, %s, %s", format, OutputText, OutputJSON, OutputJSONPassthrough)
+  }
+  return nil
+}
+
+func IsJSONOutput() bool {
+  return outputFormat != OutputText
+}
+
+/**
+ * Sets the name of the plugin that the following events are attributed to,
+ * or an empty string if they come from terraform-wheels itself
+ */
+func SetEventPlugin(name string) {
+  eventLock.Lock()
+  defer eventLock.Unlock()
+  eventPlugin = name
+}
+
+/**
+ * Emits an event. In text mode only the message is shown, if there is one,
+ * while in JSON mode the event is written as a single JSON line, together
+ * with the given fields.
+ */
+func PrintEvent(level string, event string, fields map[string]interface{}, format string, a ...interface{}) {
+  message := ""
+  if format != "" {
+    message = fmt.Sprintf(format, a...)
+  }
+
+  if !IsJSONOutput() {
+    if message == "" {
+      return
+    }
+    switch level {
+    case "error":
+      colorableStderr.Write([]byte(fmt.Sprintf("%s %s\n", Red("Error:"), message)))
+    case "warning":
+      colorableStdout.Write([]byte(fmt.Sprintf("%s %s\n", Bold(Yellow("Warn: ")), message)))
+    case "info":
+      colorableStdout.Write([]byte(fmt.Sprintf("%s %s\n", Cyan("Info: "), message)))
+    default:
+      colorableStdout.Write([]byte(message + "\n"))
+    }
+    return
+  }
+
+  eventLock.Lock()
+  defer eventLock.Unlock()
+
+  data := make(map[string]interface{})
+  for k, v := range fields {
+    data[k] = v
+  }
+  data["time"] = time.Now().UTC().Format(time.RFC3339)
+  data["level"] = level
+  if event != "" {
+    data["event"] = event
+  }
+  if eventPlugin != "" {
+    data["plugin"] = eventPlugin
+  }
+  if message != "" {
+    data["message"] = ansiEscapeRe.ReplaceAllString(message, "")
+  }
+
+  line, err := json.Marshal(data)
+  if err != nil {
+    line, _ = json.Marshal(map[string]string{"level": "error", "message": err.Error()})
+  }
+  colorableStdout.Write(append(line, '\n'))
+}
+
+/**
+ * A writer that emits every line written to it as an output event
+ */
+type eventLineWriter struct {
+  stream string
+  buf    bytes.Buffer
+}
+
+func (w *eventLineWriter) Write(p []byte) (int, error) {
+  w.buf.Write(p)
+  for {
+    idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+    if idx < 0 {
+      break
+    }
+    line := string(w.buf.Next(idx + 1))
+    w.emit(line[:len(line)-1])
+  }
+  return len(p), nil
+}
+
+func (w *eventLineWriter) Flush() {
+  if w.buf.Len() > 0 {
+    w.emit(w.buf.String())
+    w.buf.Reset()
+  }
+}
+
+func (w *eventLineWriter) emit(line string) {
+  PrintEvent("output", "output", map[string]interface{}{"stream": w.stream}, "%s", ansiEscapeRe.ReplaceAllString(line, ""))
+}
+
+/**
+ * Copies the stdout and stderr of a process in the background. The returned
+ * group completes when both streams are drained, which must happen before
+ * waiting for the process, since that closes the pipes.
+ */
+func passthroughProcessOutput(stdout io.Reader, stderr io.Reader) *sync.WaitGroup {
+  wg := &sync.WaitGroup{}
+  wg.Add(2)
+  go func() {
+    defer wg.Done()
+    copyProcessOutput("stdout", stdout)
+  }()
+  go func() {
+    defer wg.Done()
+    copyProcessOutput("stderr", stderr)
+  }()
+  return wg
+}
+
+/**
+ * Copies the output of a process to our stdout or stderr, wrapping every
+ * line as an event when in JSON mode
+ */
+func copyProcessOutput(stream string, reader io.Reader) {
+  if outputFormat != OutputJSON {
+    if stream == "stderr" {
+      _, _ = io.Copy(colorableStderr, reader)
+    } else {
+      _, _ = io.Copy(colorableStdout, reader)
+    }
+    return
+  }
+
+  w := &eventLineWriter{stream: stream}
+  _, _ = io.Copy(w, reader)
+  w.Flush()
+}
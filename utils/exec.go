@@ -1,13 +1,14 @@
 package utils
 
 import (
+  "bytes"
   "fmt"
-  "io"
   "io/ioutil"
   "os"
   "os/exec"
   "os/signal"
   "strings"
+  "sync"
   "syscall"
 )
 
@@ -52,12 +53,7 @@ func ExecuteAndPassthrough(env []string, binary string, args ...string) (int, er
   }
 
   // Async readers of the Stdout/Err
-  go func() {
-    _, _ = io.Copy(colorableStdout, stdout)
-  }()
-  go func() {
-    _, _ = io.Copy(colorableStderr, stderr)
-  }()
+  outputDone := passthroughProcessOutput(stdout, stderr)
 
   // Forward interrupt signals to the launched process
   sigs := make(chan os.Signal, 1)
@@ -73,8 +69,9 @@ func ExecuteAndPassthrough(env []string, binary string, args ...string) (int, er
   }()
 
   // Wait until the command is completed and remove the signal handlers
+  outputDone.Wait()
   err = cmd.Wait()
-  signal.Reset(syscall.SIGINT, syscall.SIGTERM)
+  signal.Stop(sigs)
   sigs <- syscall.SIGINT
 
   if err != nil {
@@ -112,13 +109,9 @@ func ExecuteInFolderAndPassthrough(workDir string, binary string, args ...string
   }
 
   // Async readers of the Stdout/Err
-  go func() {
-    _, _ = io.Copy(colorableStdout, stdout)
-  }()
-  go func() {
-    _, _ = io.Copy(colorableStderr, stderr)
-  }()
+  outputDone := passthroughProcessOutput(stdout, stderr)
 
+  outputDone.Wait()
   if err := cmd.Wait(); err != nil {
     // Get exit code on non-zero exits
     if exiterr, ok := err.(*exec.ExitError); ok {
@@ -181,9 +174,9 @@ func ExecuteAndCollect(env []string, binary string, args ...string) (int, string
 /**
  * Change directory and run the given command and pipe stdout/stderr
  */
-func ShellExecuteInFolderAndPassthrough(workDir string, cmdline string) (int, error) {
+func ShellExecuteInFolderAndPassthrough(workDir string, env []string, cmdline string) (int, error) {
   cmd := exec.Command("sh", "-c", cmdline)
-  cmd.Env = os.Environ()
+  cmd.Env = updateEnv(os.Environ(), env)
   cmd.Dir = workDir
 
   stdout, err := cmd.StdoutPipe()
@@ -199,13 +192,9 @@ func ShellExecuteInFolderAndPassthrough(workDir string, cmdline string) (int, er
   }
 
   // Async readers of the Stdout/Err
-  go func() {
-    _, _ = io.Copy(colorableStdout, stdout)
-  }()
-  go func() {
-    _, _ = io.Copy(colorableStderr, stderr)
-  }()
+  outputDone := passthroughProcessOutput(stdout, stderr)
 
+  outputDone.Wait()
   if err := cmd.Wait(); err != nil {
     // Get exit code on non-zero exits
     if exiterr, ok := err.(*exec.ExitError); ok {
@@ -267,3 +256,53 @@ func ShellExecuteSilently(cmdline string) (int, error) {
 
   return 0, nil
 }
+
+/**
+ * Change directory and run the given command, feeding `input` to stdin,
+ * collecting stdout and passing stderr through
+ */
+func ExecuteInFolderWithInput(workDir string, env []string, input []byte, binary string, args ...string) (int, string, error) {
+  cmd := exec.Command(binary, args...)
+  cmd.Env = updateEnv(os.Environ(), env)
+  cmd.Dir = workDir
+  cmd.Stdin = bytes.NewReader(input)
+
+  stdout, err := cmd.StdoutPipe()
+  if err != nil {
+    return 0, "", fmt.Errorf("Unable to open StdOut Pipe: %s", err.Error())
+  }
+  stderr, err := cmd.StderrPipe()
+  if err != nil {
+    return 0, "", fmt.Errorf("Unable to open StdErr Pipe: %s", err.Error())
+  }
+  if err := cmd.Start(); err != nil {
+    return 0, "", err
+  }
+
+  // Async reader of the StdErr, that must be drained before waiting
+  stderrDone := &sync.WaitGroup{}
+  stderrDone.Add(1)
+  go func() {
+    defer stderrDone.Done()
+    copyProcessOutput("stderr", stderr)
+  }()
+
+  ssout, err := ioutil.ReadAll(stdout)
+  if err != nil {
+    return 0, "", fmt.Errorf("Unable to read stdout: %s", err.Error())
+  }
+
+  stderrDone.Wait()
+  if err := cmd.Wait(); err != nil {
+    // Get exit code on non-zero exits
+    if exiterr, ok := err.(*exec.ExitError); ok {
+      if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
+        return status.ExitStatus(), string(ssout), nil
+      }
+    } else {
+      return 0, string(ssout), err
+    }
+  }
+
+  return 0, string(ssout), nil
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+  "bufio"
+  "encoding/json"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "runtime"
+  "strings"
+)
+
+type gcpCredentials struct {
+  Type      string `json:"type"`
+  ProjectID string `json:"project_id"`
+}
+
+func getGCloudConfigDir() string {
+  if dir, ok := os.LookupEnv("CLOUDSDK_CONFIG"); ok {
+    return dir
+  }
+  if runtime.GOOS == "windows" {
+    return filepath.Join(os.Getenv("APPDATA"), "gcloud")
+  }
+  home, err := os.UserHomeDir()
+  if err != nil {
+    return ""
+  }
+  return filepath.Join(home, ".config", "gcloud")
+}
+
+/**
+ * Returns the path of the credentials file terraform is going to use, the
+ * same way the google provider looks for it
+ */
+func getGCPCredentialsPath() string {
+  for _, env := range []string{"GOOGLE_CREDENTIALS", "GOOGLE_CLOUD_KEYFILE_JSON", "GCLOUD_KEYFILE_JSON", "GOOGLE_APPLICATION_CREDENTIALS"} {
+    if path := os.Getenv(env); path != "" {
+      return path
+    }
+  }
+
+  dir := getGCloudConfigDir()
+  if dir == "" {
+    return ""
+  }
+  return filepath.Join(dir, "application_default_credentials.json")
+}
+
+func readGCPCredentials() *gcpCredentials {
+  path := getGCPCredentialsPath()
+  if path == "" {
+    return nil
+  }
+
+  // The GOOGLE_CREDENTIALS variable can also contain the JSON itself
+  content := []byte(path)
+  if !strings.HasPrefix(strings.TrimSpace(path), "{") {
+    var err error
+    content, err = ioutil.ReadFile(path)
+    if err != nil {
+      return nil
+    }
+  }
+
+  var creds gcpCredentials
+  if json.Unmarshal(content, &creds) != nil || creds.Type == "" {
+    return nil
+  }
+  return &creds
+}
+
+/**
+ * Checks if there are GCP credentials in a service account key file or in
+ * the application default credentials of `gcloud auth application-default login`
+ */
+func IsGCPCredsOK() bool {
+  return readGCPCredentials() != nil
+}
+
+/**
+ * Returns the GCP project to use, or an empty string if it could not be found
+ */
+func GetGCPProject() string {
+  for _, env := range []string{"GOOGLE_PROJECT", "GOOGLE_CLOUD_PROJECT", "GCLOUD_PROJECT", "CLOUDSDK_CORE_PROJECT"} {
+    if project := os.Getenv(env); project != "" {
+      return project
+    }
+  }
+
+  if creds := readGCPCredentials(); creds != nil && creds.ProjectID != "" {
+    return creds.ProjectID
+  }
+
+  // Use the project of the active gcloud configuration
+  dir := getGCloudConfigDir()
+  if dir == "" {
+    return ""
+  }
+  config := "default"
+  if active, err := ioutil.ReadFile(filepath.Join(dir, "active_config")); err == nil {
+    config = strings.TrimSpace(string(active))
+  }
+
+  file, err := os.Open(filepath.Join(dir, "configurations", "config_"+config))
+  if err != nil {
+    return ""
+  }
+  defer file.Close()
+
+  scanner := bufio.NewScanner(file)
+  for scanner.Scan() {
+    kv := strings.SplitN(scanner.Text(), "=", 2)
+    if len(kv) == 2 && strings.TrimSpace(kv[0]) == "project" {
+      return strings.TrimSpace(kv[1])
+    }
+  }
+  return ""
+}
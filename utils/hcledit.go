@@ -0,0 +1,416 @@
+package utils
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "regexp"
+  "sort"
+  "strconv"
+  "strings"
+
+  "github.com/hashicorp/hcl/hcl/ast"
+  "github.com/hashicorp/hcl/hcl/parser"
+  "github.com/hashicorp/hcl/hcl/printer"
+  "github.com/hashicorp/hcl/hcl/token"
+)
+
+// The attribute edits use the AST only to locate the exact byte ranges of the
+// attributes, and then splice the text, so that everything else in the file
+// (including the comments and the formatting) is kept as-is.
+
+/**
+ * Returns a string value node
+ */
+func HCLString(value string) ast.Node {
+  // The quotes in interpolations must not be escaped
+  if strings.Contains(value, "${") && !strings.ContainsAny(value, "\n\\") {
+    return &ast.LiteralType{Token: token.Token{Type: token.STRING, Text: `"` + value + `"`}}
+  }
+
+  var buf bytes.Buffer
+  enc := json.NewEncoder(&buf)
+  enc.SetEscapeHTML(false)
+  enc.Encode(value)
+  return &ast.LiteralType{Token: token.Token{Type: token.STRING, Text: strings.TrimSuffix(buf.String(), "\n")}}
+}
+
+/**
+ * Returns a number value node
+ */
+func HCLNumber(value string) ast.Node {
+  return &ast.LiteralType{Token: token.Token{Type: token.NUMBER, Text: value}}
+}
+
+/**
+ * Returns a boolean value node
+ */
+func HCLBool(value bool) ast.Node {
+  return &ast.LiteralType{Token: token.Token{Type: token.BOOL, Text: strconv.FormatBool(value)}}
+}
+
+/**
+ * Returns a list value node with the given items
+ */
+func HCLList(items []ast.Node) ast.Node {
+  return &ast.ListType{List: items}
+}
+
+/**
+ * Returns an object value node with the given (sorted) keys
+ */
+func HCLMap(values map[string]ast.Node) ast.Node {
+  var keys []string
+  for k := range values {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+
+  obj := &ast.ObjectType{List: &ast.ObjectList{}}
+  for _, k := range keys {
+    obj.List.Add(hclObjectItem(k, true, values[k]))
+  }
+  return obj
+}
+
+/**
+ * Parses a value given in the command-line. Numbers, booleans, quoted strings,
+ * lists and maps are used as-is, and everything else as a string.
+ */
+func ParseHCLValue(text string) ast.Node {
+  file, err := parser.Parse([]byte("value = " + text))
+  if err != nil {
+    return HCLString(text)
+  }
+
+  root, ok := file.Node.(*ast.ObjectList)
+  if !ok || len(root.Items) != 1 || len(root.Items[0].Keys) != 1 {
+    return HCLString(text)
+  }
+  return rebuildHCLValue(root.Items[0].Val)
+}
+
+/**
+ * Rebuilds a parsed value without the positions of the source, so that it's
+ * printed like the values built with the HCL* functions
+ */
+func rebuildHCLValue(node ast.Node) ast.Node {
+  switch v := node.(type) {
+  case *ast.LiteralType:
+    return &ast.LiteralType{Token: token.Token{Type: v.Token.Type, Text: v.Token.Text}}
+  case *ast.ListType:
+    var items []ast.Node
+    for _, item := range v.List {
+      items = append(items, rebuildHCLValue(item))
+    }
+    return HCLList(items)
+  case *ast.ObjectType:
+    values := make(map[string]ast.Node)
+    for _, item := range v.List.Items {
+      var keys []string
+      for _, key := range item.Keys {
+        keys = append(keys, hclKeyText(key))
+      }
+      values[strings.Join(keys, ".")] = rebuildHCLValue(item.Val)
+    }
+    return HCLMap(values)
+  }
+  return node
+}
+
+func hclObjectItem(name string, quoted bool, value ast.Node) *ast.ObjectItem {
+  keyToken := token.Token{Type: token.IDENT, Text: name}
+  if quoted {
+    v, _ := json.Marshal(name)
+    keyToken = token.Token{Type: token.STRING, Text: string(v)}
+  }
+
+  return &ast.ObjectItem{
+    Keys:   []*ast.ObjectKey{{Token: keyToken}},
+    Assign: token.Pos{Line: 1, Column: 1},
+    Val:    value,
+  }
+}
+
+/**
+ * Renders a single `name = value` attribute
+ */
+func renderHCLAttribute(name string, quoted bool, value ast.Node) (string, error) {
+  var buf bytes.Buffer
+  err := printer.Fprint(&buf, hclObjectItem(name, quoted, value))
+  if err != nil {
+    return "", fmt.Errorf("Could not render %s: %s", name, err.Error())
+  }
+
+  // The printer separates the items of the built objects with blank lines,
+  // since they have no positions
+  rendered := buf.String()
+  if !strings.Contains(rendered, "<<") {
+    var lines []string
+    for _, line := range strings.Split(rendered, "\n") {
+      if strings.TrimSpace(line) != "" {
+        lines = append(lines, line)
+      }
+    }
+    rendered = strings.Join(lines, "\n")
+  }
+  return rendered, nil
+}
+
+/**
+ * Renders a value as it would appear on the right side of an attribute
+ */
+func renderHCLValue(value ast.Node) (string, error) {
+  rendered, err := renderHCLAttribute("v", false, value)
+  if err != nil {
+    return "", err
+  }
+  return strings.TrimPrefix(rendered, "v = "), nil
+}
+
+func hclKeyText(key *ast.ObjectKey) string {
+  if key.Token.Type == token.STRING {
+    if v, err := strconv.Unquote(key.Token.Text); err == nil {
+      return v
+    }
+  }
+  return key.Token.Text
+}
+
+/**
+ * Finds the object at the given path, where the path is composed of the keys
+ * of the blocks and attributes to descend into (ex. `module`, `dcos`, `tags`)
+ */
+func findHCLObject(list *ast.ObjectList, path []string) *ast.ObjectType {
+  if len(path) == 0 {
+    return nil
+  }
+
+  for _, item := range list.Items {
+    if len(item.Keys) > len(path) {
+      continue
+    }
+
+    matches := true
+    for i, key := range item.Keys {
+      if hclKeyText(key) != path[i] {
+        matches = false
+        break
+      }
+    }
+    if !matches {
+      continue
+    }
+
+    obj, ok := item.Val.(*ast.ObjectType)
+    if !ok {
+      continue
+    }
+    if len(item.Keys) == len(path) {
+      return obj
+    }
+    if found := findHCLObject(obj.List, path[len(item.Keys):]); found != nil {
+      return found
+    }
+  }
+
+  return nil
+}
+
+/**
+ * Returns the attributes (single-key items) with the given name
+ */
+func findHCLAttributes(obj *ast.ObjectType, name string) []*ast.ObjectItem {
+  var ret []*ast.ObjectItem
+  for _, item := range obj.List.Items {
+    if len(item.Keys) == 1 && hclKeyText(item.Keys[0]) == name {
+      ret = append(ret, item)
+    }
+  }
+  return ret
+}
+
+/**
+ * Returns the byte offset right after the end of the given value
+ */
+func hclNodeEnd(node ast.Node) int {
+  switch v := node.(type) {
+  case *ast.LiteralType:
+    return v.Token.Pos.Offset + len(v.Token.Text)
+  case *ast.ListType:
+    return v.Rbrack.Offset + 1
+  case *ast.ObjectType:
+    return v.Rbrace.Offset + 1
+  }
+  return node.Pos().Offset
+}
+
+func parseHCLObject(content []byte, path []string) (*ast.ObjectType, error) {
+  file, err := parser.Parse(content)
+  if err != nil {
+    return nil, err
+  }
+
+  root, ok := file.Node.(*ast.ObjectList)
+  if !ok {
+    return nil, fmt.Errorf("Unexpected document structure")
+  }
+
+  // The root of the document is an object without braces
+  if len(path) == 0 {
+    return &ast.ObjectType{List: root, Rbrace: token.Pos{Offset: len(content)}}, nil
+  }
+
+  obj := findHCLObject(root, path)
+  if obj == nil {
+    return nil, fmt.Errorf("Could not find '%s'", strings.Join(path, "."))
+  }
+  return obj, nil
+}
+
+/**
+ * Sets the attribute `name` of the object at the given path (or of the root
+ * of the document if the path is empty), replacing the value in-place if the
+ * attribute exists (removing any duplicates), or adding it at the end of the
+ * object otherwise
+ */
+func SetHCLAttribute(content []byte, path []string, name string, value ast.Node) ([]byte, error) {
+  obj, err := parseHCLObject(content, path)
+  if err != nil {
+    return nil, err
+  }
+
+  attrs := findHCLAttributes(obj, name)
+  if len(attrs) == 0 {
+    // Follow the style of the object for the keys, and always quote the keys
+    // that are not valid identifiers
+    quoted := !hclIdentRe.MatchString(name)
+    for _, item := range obj.List.Items {
+      if len(item.Keys) == 1 && item.Keys[0].Token.Type == token.STRING {
+        quoted = true
+      }
+    }
+
+    rendered, err := renderHCLAttribute(name, quoted, value)
+    if err != nil {
+      return nil, err
+    }
+
+    if len(path) == 0 {
+      var buf bytes.Buffer
+      buf.Write(bytes.TrimRight(content, "\n"))
+      if buf.Len() > 0 {
+        buf.WriteString("\n")
+      }
+      buf.WriteString(rendered + "\n")
+      return buf.Bytes(), nil
+    }
+
+    rbrace := obj.Rbrace.Offset
+    lineStart := bytes.LastIndexByte(content[:rbrace], '\n') + 1
+    if len(bytes.TrimSpace(content[lineStart:rbrace])) == 0 {
+      return spliceBytes(content, lineStart, lineStart, "  "+rendered+"\n"), nil
+    }
+    return spliceBytes(content, rbrace, rbrace, "\n"+rendered+"\n"), nil
+  }
+
+  // Remove the duplicates from the end, so the offsets stay valid
+  for i := len(attrs) - 1; i > 0; i-- {
+    start, end := hclItemRange(content, attrs[i])
+    content = spliceBytes(content, start, end, "")
+  }
+
+  // Replace only the value, so the alignment of the attributes is kept
+  renderedValue, err := renderHCLValue(value)
+  if err != nil {
+    return nil, err
+  }
+  first := attrs[0]
+  keyStart := first.Keys[0].Pos().Offset
+  lineStart := bytes.LastIndexByte(content[:keyStart], '\n') + 1
+  if indent := content[lineStart:keyStart]; len(bytes.TrimSpace(indent)) == 0 {
+    renderedValue = strings.ReplaceAll(renderedValue, "\n", "\n"+string(indent))
+  }
+  return spliceBytes(content, first.Val.Pos().Offset, hclNodeEnd(first.Val), renderedValue), nil
+}
+
+/**
+ * Removes the attribute `name` of the object at the given path, together
+ * with its comments
+ */
+func UnsetHCLAttribute(content []byte, path []string, name string) ([]byte, error) {
+  obj, err := parseHCLObject(content, path)
+  if err != nil {
+    return nil, err
+  }
+
+  attrs := findHCLAttributes(obj, name)
+  for i := len(attrs) - 1; i >= 0; i-- {
+    start, end := hclItemRange(content, attrs[i])
+    content = spliceBytes(content, start, end, "")
+  }
+  return content, nil
+}
+
+/**
+ * Returns the value of the attribute `name` of the object at the given path,
+ * or nil if it does not exist
+ */
+func GetHCLAttribute(content []byte, path []string, name string) (ast.Node, error) {
+  obj, err := parseHCLObject(content, path)
+  if err != nil {
+    return nil, err
+  }
+
+  attrs := findHCLAttributes(obj, name)
+  if len(attrs) == 0 {
+    return nil, nil
+  }
+  return attrs[len(attrs)-1].Val, nil
+}
+
+var hclIdentRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+/**
+ * Returns the byte range of an item including its comments, extended to whole
+ * lines if nothing else is on them
+ */
+func hclItemRange(content []byte, item *ast.ObjectItem) (int, int) {
+  start := item.Keys[0].Pos().Offset
+  end := hclNodeEnd(item.Val)
+  if item.LeadComment != nil && len(item.LeadComment.List) > 0 {
+    start = item.LeadComment.List[0].Start.Offset
+  }
+  if item.LineComment != nil && len(item.LineComment.List) > 0 {
+    last := item.LineComment.List[len(item.LineComment.List)-1]
+    end = last.Start.Offset + len(last.Text)
+  }
+
+  lineStart := bytes.LastIndexByte(content[:start], '\n') + 1
+  lineEnd := bytes.IndexByte(content[end:], '\n')
+  if lineEnd < 0 {
+    lineEnd = len(content)
+  } else {
+    lineEnd += end
+  }
+
+  // Trailing commas belong to the item
+  rest := bytes.TrimSpace(content[end:lineEnd])
+  if len(bytes.TrimPrefix(rest, []byte(","))) == 0 && len(bytes.TrimSpace(content[lineStart:start])) == 0 {
+    start = lineStart
+    end = lineEnd
+    if end < len(content) {
+      end++
+    }
+  }
+
+  return start, end
+}
+
+func spliceBytes(content []byte, start int, end int, text string) []byte {
+  var buf bytes.Buffer
+  buf.Write(content[:start])
+  buf.WriteString(text)
+  buf.Write(content[end:])
+  return buf.Bytes()
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+  "testing"
+)
+
+var testModule string = `# The cluster
+module "dcos" {
+  source = "dcos-terraform/dcos/aws"
+
+  # The number of masters
+  num_masters        = 1
+  num_private_agents = 2
+
+  tags = {
+    owner = "me"
+  }
+}
+`
+
+func TestSetHCLAttribute(t *testing.T) {
+  tests := []struct {
+    name     string
+    path     []string
+    attr     string
+    value    string
+    expected string
+  }{
+    {
+      "replaces the value and keeps the comments and the alignment",
+      []string{"module", "dcos"}, "num_masters", "3",
+      `# The cluster
+module "dcos" {
+  source = "dcos-terraform/dcos/aws"
+
+  # The number of masters
+  num_masters        = 3
+  num_private_agents = 2
+
+  tags = {
+    owner = "me"
+  }
+}
+`,
+    },
+    {
+      "adds a missing attribute at the end of the block",
+      []string{"module", "dcos"}, "dcos_variant", `"ee"`,
+      `# The cluster
+module "dcos" {
+  source = "dcos-terraform/dcos/aws"
+
+  # The number of masters
+  num_masters        = 1
+  num_private_agents = 2
+
+  tags = {
+    owner = "me"
+  }
+  dcos_variant = "ee"
+}
+`,
+    },
+    {
+      "sets lists",
+      []string{"module", "dcos"}, "admin_ips", `["1.2.3.4/32", "5.6.7.8/32"]`,
+      `# The cluster
+module "dcos" {
+  source = "dcos-terraform/dcos/aws"
+
+  # The number of masters
+  num_masters        = 1
+  num_private_agents = 2
+
+  tags = {
+    owner = "me"
+  }
+  admin_ips = ["1.2.3.4/32", "5.6.7.8/32"]
+}
+`,
+    },
+    {
+      "replaces maps, which are aligned by terraform fmt later",
+      []string{"module", "dcos"}, "tags", `{owner = "you", team = "ml"}`,
+      `# The cluster
+module "dcos" {
+  source = "dcos-terraform/dcos/aws"
+
+  # The number of masters
+  num_masters        = 1
+  num_private_agents = 2
+
+  tags = {
+    "owner" = "you"
+    "team" = "ml"
+  }
+}
+`,
+    },
+    {
+      "sets attributes of nested blocks",
+      []string{"module", "dcos", "tags"}, "owner", `"you"`,
+      `# The cluster
+module "dcos" {
+  source = "dcos-terraform/dcos/aws"
+
+  # The number of masters
+  num_masters        = 1
+  num_private_agents = 2
+
+  tags = {
+    owner = "you"
+  }
+}
+`,
+    },
+  }
+
+  for _, test := range tests {
+    content, err := SetHCLAttribute([]byte(testModule), test.path, test.attr, ParseHCLValue(test.value))
+    if err != nil {
+      t.Errorf("%s: unexpected error: %s", test.name, err.Error())
+      continue
+    }
+    if string(content) != test.expected {
+      t.Errorf("%s: expected:\n%s\ngot:\n%s", test.name, test.expected, string(content))
+    }
+  }
+}
+
+func TestSetHCLAttributeRemovesDuplicates(t *testing.T) {
+  content, err := SetHCLAttribute([]byte("a = 1\nb = 2\na = 3\n"), nil, "a", HCLNumber("4"))
+  if err != nil {
+    t.Fatalf("unexpected error: %s", err.Error())
+  }
+  if string(content) != "a = 4\nb = 2\n" {
+    t.Errorf("unexpected content:\n%s", string(content))
+  }
+}
+
+func TestSetHCLAttributeMissingBlock(t *testing.T) {
+  _, err := SetHCLAttribute([]byte(testModule), []string{"module", "other"}, "num_masters", HCLNumber("3"))
+  if err == nil {
+    t.Errorf("expected an error for a missing block")
+  }
+}
+
+func TestUnsetHCLAttribute(t *testing.T) {
+  content, err := UnsetHCLAttribute([]byte(testModule), []string{"module", "dcos"}, "num_masters")
+  if err != nil {
+    t.Fatalf("unexpected error: %s", err.Error())
+  }
+
+  // The comment of the attribute is removed with it
+  expected := `# The cluster
+module "dcos" {
+  source = "dcos-terraform/dcos/aws"
+
+  num_private_agents = 2
+
+  tags = {
+    owner = "me"
+  }
+}
+`
+  if string(content) != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, string(content))
+  }
+
+  // Removing a missing attribute changes nothing
+  content, err = UnsetHCLAttribute([]byte(testModule), []string{"module", "dcos"}, "num_public_agents")
+  if err != nil {
+    t.Fatalf("unexpected error: %s", err.Error())
+  }
+  if string(content) != testModule {
+    t.Errorf("expected no changes, got:\n%s", string(content))
+  }
+}
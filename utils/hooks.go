@@ -0,0 +1,68 @@
+package utils
+
+import (
+  "fmt"
+  "regexp"
+  "strings"
+
+  . "github.com/logrusorgru/aurora"
+)
+
+var hookEnvNameRe = regexp.MustCompile(`[^A-Z0-9_]`)
+
+/**
+ * Runs the hooks configured for the given phase (`pre` or `post`) of a
+ * terraform command, in the project directory. The hooks get the environment
+ * of terraform, together with:
+ *
+ *   WHEELS_COMMAND          The terraform command (ex. `apply`)
+ *   WHEELS_PHASE            The hook phase, `pre` or `post`
+ *   WHEELS_PROJECT_DIR      The project directory
+ *   WHEELS_EXIT_CODE        The exit code of terraform (post hooks only)
+ *   WHEELS_TF_OUTPUT_<NAME> The terraform outputs of the cluster (post hooks
+ *                           only)
+ *
+ * The first hook that exits with a non-zero code stops the rest and fails.
+ */
+func RunHooks(project *ProjectSandbox, tf *TerraformWrapper, command string, phase string) error {
+  hooks := project.GetConfig().GetHooks(command, phase)
+  if len(hooks) == 0 {
+    return nil
+  }
+
+  env := append([]string{}, tf.env...)
+  env = append(env,
+    "WHEELS_COMMAND="+command,
+    "WHEELS_PHASE="+phase,
+    "WHEELS_PROJECT_DIR="+project.GetBaseDir(),
+  )
+  if phase == "post" {
+    env = append(env, fmt.Sprintf("WHEELS_EXIT_CODE=%d", tf.GetExitCode()))
+
+    // There are no outputs before the cluster is created, that's fine
+    if outputs, err := tf.GetOutputs(); err == nil {
+      for name, value := range outputs {
+        envName := hookEnvNameRe.ReplaceAllString(strings.ToUpper(name), "_")
+        env = append(env, fmt.Sprintf("WHEELS_TF_OUTPUT_%s=%s", envName, value))
+      }
+    }
+  }
+
+  for _, hook := range hooks {
+    PrintEvent("info", "hook_started", map[string]interface{}{
+      "command": command,
+      "phase":   phase,
+      "hook":    hook,
+    }, "Running %s-%s hook: %s", phase, command, Bold(hook))
+
+    code, err := ShellExecuteInFolderAndPassthrough(project.GetBaseDir(), env, hook)
+    if err != nil {
+      return fmt.Errorf("Could not run %s-%s hook: %s", phase, command, err.Error())
+    }
+    if code != 0 {
+      return fmt.Errorf("The %s-%s hook `%s` exited with code %d", phase, command, hook, code)
+    }
+  }
+
+  return nil
+}
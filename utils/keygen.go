@@ -13,17 +13,12 @@ import (
 )
 
 func CreatePublicRSAKeyFromPrivate(contents []byte, savePublicFileTo string) error {
-  privateKey, err := parsePrivateKey(contents, "")
-  if err != nil {
-    return fmt.Errorf("Error parsing private key: %s", err.Error())
-  }
-
-  publicKeyBytes, err := generatePublicKey(&privateKey.PublicKey)
+  publicKeyBytes, err := GeneratePublicRSAKey(contents)
   if err != nil {
-    return fmt.Errorf("Error generating public key: %s", err.Error())
+    return err
   }
 
-  err = writeKeyToFile([]byte(publicKeyBytes), savePublicFileTo)
+  err = writeKeyToFile(publicKeyBytes, savePublicFileTo)
   if err != nil {
     return fmt.Errorf("Error writing public key: %s", err.Error())
   }
@@ -31,27 +26,36 @@ func CreatePublicRSAKeyFromPrivate(contents []byte, savePublicFileTo string) err
   return nil
 }
 
-func CreateRSAKeyPair(savePrivateFileTo string, savePublicFileTo string) error {
-  bitSize := 2048
-
-  privateKey, err := generatePrivateKey(bitSize)
+/**
+ * Returns the authorized_keys public key of a PEM private key, without
+ * writing it
+ */
+func GeneratePublicRSAKey(contents []byte) ([]byte, error) {
+  privateKey, err := parsePrivateKey(contents, "")
   if err != nil {
-    return fmt.Errorf("Error generating private key: %s", err.Error())
+    return nil, fmt.Errorf("Error parsing private key: %s", err.Error())
   }
 
   publicKeyBytes, err := generatePublicKey(&privateKey.PublicKey)
   if err != nil {
-    return fmt.Errorf("Error generating public key: %s", err.Error())
+    return nil, fmt.Errorf("Error generating public key: %s", err.Error())
   }
 
-  privateKeyBytes := encodePrivateKeyToPEM(privateKey)
+  return publicKeyBytes, nil
+}
+
+func CreateRSAKeyPair(savePrivateFileTo string, savePublicFileTo string) error {
+  privateKeyBytes, publicKeyBytes, err := GenerateRSAKeyPair()
+  if err != nil {
+    return err
+  }
 
   err = writeKeyToFile(privateKeyBytes, savePrivateFileTo)
   if err != nil {
     return fmt.Errorf("Error writing private key: %s", err.Error())
   }
 
-  err = writeKeyToFile([]byte(publicKeyBytes), savePublicFileTo)
+  err = writeKeyToFile(publicKeyBytes, savePublicFileTo)
   if err != nil {
     return fmt.Errorf("Error writing public key: %s", err.Error())
   }
@@ -59,6 +63,26 @@ func CreateRSAKeyPair(savePrivateFileTo string, savePublicFileTo string) error {
   return nil
 }
 
+/**
+ * Generates an RSA key pair and returns the PEM private key and the
+ * authorized_keys public key, without writing them
+ */
+func GenerateRSAKeyPair() ([]byte, []byte, error) {
+  bitSize := 2048
+
+  privateKey, err := generatePrivateKey(bitSize)
+  if err != nil {
+    return nil, nil, fmt.Errorf("Error generating private key: %s", err.Error())
+  }
+
+  publicKeyBytes, err := generatePublicKey(&privateKey.PublicKey)
+  if err != nil {
+    return nil, nil, fmt.Errorf("Error generating public key: %s", err.Error())
+  }
+
+  return encodePrivateKeyToPEM(privateKey), publicKeyBytes, nil
+}
+
 func parsePrivateKey(contents []byte, rsaPrivateKeyPassword string) (*rsa.PrivateKey, error) {
   var err error
 
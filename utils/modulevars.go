@@ -0,0 +1,282 @@
+package utils
+
+import (
+  "encoding/json"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+
+  "github.com/hashicorp/hcl"
+)
+
+type ModuleVariable struct {
+  Name        string
+  Type        string // `string`, `list` or `map`
+  Description string
+  Default     interface{}
+}
+
+type ModuleVariableSnapshot struct {
+  Provider  string
+  Version   string // The version prefix the snapshot applies to
+  Variables []ModuleVariable
+}
+
+type terraformModuleManifest struct {
+  Modules []struct {
+    Source  string
+    Version string
+    Dir     string
+  }
+}
+
+/**
+ * Returns the default value in the format used in the command-line, or an
+ * empty string if there is none
+ */
+func (v ModuleVariable) FormatDefault() string {
+  switch tv := v.Default.(type) {
+  case nil:
+    return ""
+  case string:
+    return tv
+  case []interface{}:
+    var items []string
+    for _, item := range tv {
+      items = append(items, fmt.Sprintf("%v", item))
+    }
+    return strings.Join(items, ",")
+  case map[string]interface{}:
+    if len(tv) == 0 {
+      return ""
+    }
+    bt, _ := json.Marshal(tv)
+    return string(bt)
+  }
+  return fmt.Sprintf("%v", v.Default)
+}
+
+func normalizeModuleVersion(version string) string {
+  return strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+/**
+ * Returns the directory where the modules downloaded by terraform-wheels
+ * are cached
+ */
+func GetModuleCacheDir(provider string, version string) (string, error) {
+  home, err := os.UserHomeDir()
+  if err != nil {
+    return "", fmt.Errorf("Could not find the home directory: %s", err.Error())
+  }
+
+  return filepath.Join(home, ".terraform-wheels", "modules", provider, normalizeModuleVersion(version)), nil
+}
+
+/**
+ * Loads the variables of the dcos-terraform module of the given cloud provider
+ * and version, looking in order at:
+ *
+ *  - The module checkout of `terraform init` in the project
+ *  - The module cache in ~/.terraform-wheels/modules, downloading the module
+ *    from GitHub if it's missing
+ *  - The snapshot compiled in terraform-wheels
+ */
+func LoadModuleVariables(project *ProjectSandbox, provider string, version string) ([]ModuleVariable, error) {
+  return loadModuleVariables(project, provider, version, true)
+}
+
+/**
+ * Like LoadModuleVariables, but never downloads the module. Used for the help
+ * pages, that should not wait for the network.
+ */
+func LoadLocalModuleVariables(project *ProjectSandbox, provider string, version string) ([]ModuleVariable, error) {
+  return loadModuleVariables(project, provider, version, false)
+}
+
+func loadModuleVariables(project *ProjectSandbox, provider string, version string, download bool) ([]ModuleVariable, error) {
+  version = normalizeModuleVersion(version)
+
+  if dir := findProjectModuleDir(project, provider, version); dir != "" {
+    if vars, err := parseModuleVariables(dir); err == nil && len(vars) > 0 {
+      return vars, nil
+    }
+  }
+
+  dir, err := GetModuleCacheDir(provider, version)
+  if err == nil && download {
+    dir, err = downloadModule(provider, version)
+  }
+  if err == nil {
+    if vars, err := parseModuleVariables(dir); err == nil && len(vars) > 0 {
+      return vars, nil
+    }
+  }
+
+  return getModuleVariablesSnapshot(provider, version)
+}
+
+/**
+ * Returns true if the arguments of a command ask for its help page
+ */
+func IsHelpRequested(args []string) bool {
+  for _, arg := range args {
+    if arg == "--" {
+      break
+    }
+    if arg == "-h" || arg == "-help" || arg == "--help" || arg == "--h" {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Returns the directory of the module checkout in the project, if terraform
+ * has already downloaded the given version
+ */
+func findProjectModuleDir(project *ProjectSandbox, provider string, version string) string {
+  content, err := ioutil.ReadFile(project.GetFilePath(filepath.Join(".terraform", "modules", "modules.json")))
+  if err != nil {
+    return ""
+  }
+
+  var manifest terraformModuleManifest
+  if json.Unmarshal(content, &manifest) != nil {
+    return ""
+  }
+
+  for _, mod := range manifest.Modules {
+    if mod.Source == "dcos-terraform/dcos/"+provider && normalizeModuleVersion(mod.Version) == version {
+      return project.GetFilePath(mod.Dir)
+    }
+  }
+  return ""
+}
+
+/**
+ * Downloads the variables of the given module version from GitHub in the
+ * module cache, if they are not already there
+ */
+func downloadModule(provider string, version string) (string, error) {
+  dir, err := GetModuleCacheDir(provider, version)
+  if err != nil {
+    return "", err
+  }
+  if _, err := os.Stat(filepath.Join(dir, "variables.tf")); err == nil {
+    return dir, nil
+  }
+
+  // Download in a temporary directory, so an interrupted download does not
+  // leave a partial module in the cache
+  tmpDir := dir + ".tmp"
+  os.RemoveAll(tmpDir)
+  err = os.MkdirAll(tmpDir, os.ModePerm)
+  if err != nil {
+    return "", fmt.Errorf("Could not create %s: %s", tmpDir, err.Error())
+  }
+  defer os.RemoveAll(tmpDir)
+
+  url := fmt.Sprintf("https://github.com/dcos-terraform/terraform-%s-dcos/archive/v%s.tar.gz", provider, version)
+  err = Download(url, WithDefaults).
+    AndDecompressIfCompressed().
+    EventuallyUntarOnlyTo(tmpDir+string(os.PathSeparator), []string{"variables.tf"}, 1)
+  if err != nil {
+    return "", err
+  }
+
+  err = os.Rename(tmpDir, dir)
+  if err != nil {
+    return "", fmt.Errorf("Could not move the module to %s: %s", dir, err.Error())
+  }
+  return dir, nil
+}
+
+/**
+ * Parses the `variable` blocks of all the terraform files in the given
+ * module directory
+ */
+func parseModuleVariables(dir string) ([]ModuleVariable, error) {
+  files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+  if err != nil {
+    return nil, err
+  }
+
+  var vars []ModuleVariable
+  for _, file := range files {
+    content, err := ioutil.ReadFile(file)
+    if err != nil {
+      return nil, fmt.Errorf("Could not read %s: %s", file, err.Error())
+    }
+
+    var parsed struct {
+      Variables []struct {
+        Name        string      `hcl:",key"`
+        Type        string      `hcl:"type"`
+        Description string      `hcl:"description"`
+        Default     interface{} `hcl:"default"`
+      } `hcl:"variable"`
+    }
+    err = hcl.Decode(&parsed, string(content))
+    if err != nil {
+      return nil, fmt.Errorf("Could not parse %s: %s", file, err.Error())
+    }
+
+    for _, v := range parsed.Variables {
+      mv := ModuleVariable{v.Name, v.Type, v.Description, v.Default}
+
+      // Maps are decoded as a list of maps
+      if maps, ok := v.Default.([]map[string]interface{}); ok {
+        merged := make(map[string]interface{})
+        for _, m := range maps {
+          for k, val := range m {
+            merged[k] = val
+          }
+        }
+        mv.Default = merged
+      }
+
+      // Terraform infers the type from the default value
+      if mv.Type == "" {
+        switch mv.Default.(type) {
+        case []interface{}:
+          mv.Type = "list"
+        case map[string]interface{}:
+          mv.Type = "map"
+        default:
+          mv.Type = "string"
+        }
+      }
+
+      vars = append(vars, mv)
+    }
+  }
+
+  sort.Slice(vars, func(i, j int) bool {
+    return vars[i].Name < vars[j].Name
+  })
+  return vars, nil
+}
+
+func getModuleVariablesSnapshot(provider string, version string) ([]ModuleVariable, error) {
+  var fallback *ModuleVariableSnapshot
+  for i, snapshot := range moduleVariableSnapshots {
+    if snapshot.Provider != provider {
+      continue
+    }
+    if strings.HasPrefix(version, snapshot.Version) {
+      return snapshot.Variables, nil
+    }
+    fallback = &moduleVariableSnapshots[i]
+  }
+
+  if fallback == nil {
+    return nil, fmt.Errorf("Could not find the variables of the %s module version %s", provider, version)
+  }
+
+  PrintWarning("Could not load the variables of the %s module version %s, using the ones of version %s.x", provider, version, fallback.Version)
+  return fallback.Variables, nil
+}
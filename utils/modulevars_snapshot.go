@@ -0,0 +1,487 @@
+package utils
+
+// The variables of the dcos-terraform modules, used when the variables of the
+// selected module version cannot be loaded from a module checkout. They can be
+// refreshed with `ProjectSandbox.PrintVariableDefs` in a module checkout.
+var moduleVariableSnapshots []ModuleVariableSnapshot = []ModuleVariableSnapshot{
+  {Provider: "aws", Version: "0.2", Variables: []ModuleVariable{
+    {Name: "accepted_internal_networks", Type: "list", Description: "Subnet ranges for all internal networks"},
+    {Name: "additional_private_agent_ips", Type: "string", Description: "Additional private agent IPs."},
+    {Name: "additional_public_agent_ips", Type: "string", Description: "Additional public agent IPs."},
+    {Name: "additional_windows_private_agent_ips", Type: "string", Description: "Additional windows private agent IPs"},
+    {Name: "additional_windows_private_agent_os_user", Type: "string", Description: "Additional windows private agent os user to be used for WinRM"},
+    {Name: "additional_windows_private_agent_passwords", Type: "string", Description: "Additional windows private agent passwords to be used for WinRM"},
+    {Name: "admin_ips", Type: "list", Description: "List of CIDR admin IPs"},
+    {Name: "ansible_additional_config", Type: "string", Description: "Add additional config options to ansible. This is getting merged with generated defaults. Do not specify `dcos:`"},
+    {Name: "ansible_bundled_container", Type: "string", Description: "Docker container with bundled dcos-ansible and ansible executables"},
+    {Name: "ansible_user", Type: "string", Description: "The Ansible user that is used to run the Ansible Tasks."},
+    {Name: "availability_zones", Type: "list", Description: "List of availability_zones to be used as the same format that are required by the platform/cloud providers. i.e `['RegionZone']`"},
+    {Name: "aws_ami", Type: "string", Description: "AMI that will be used for the instances instead of the Mesosphere chosen default images. Custom AMIs must fulfill the Mesosphere DC/OS system-requirements: See https://docs.mesosphere.com/1.12/installing/production/system-requirements/"},
+    {Name: "aws_key_name", Type: "string", Description: "Specify the aws ssh key to use. We assume its already loaded in your SSH agent. Set ssh_public_key_file to empty string"},
+    {Name: "bootstrap_associate_public_ip_address", Type: "string", Description: "[BOOTSTRAP] Associate a public ip address with there instances"},
+    {Name: "bootstrap_aws_ami", Type: "string", Description: "[BOOTSTRAP] AMI to be used"},
+    {Name: "bootstrap_hostname_format", Type: "string", Description: "[BOOTSTRAP] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "bootstrap_iam_instance_profile", Type: "string", Description: "[BOOTSTRAP] Instance profile to be used for these instances"},
+    {Name: "bootstrap_instance_type", Type: "string", Description: "[BOOTSTRAP] Instance type"},
+    {Name: "bootstrap_os", Type: "string", Description: "[BOOTSTRAP] Operating system to use. Instead of using your own AMI you could use a provided OS."},
+    {Name: "bootstrap_private_ip", Type: "string", Description: "Private IP bootstrap nginx is listening on. Used to build the bootstrap URL."},
+    {Name: "bootstrap_root_volume_size", Type: "string", Description: "[BOOTSTRAP] Root volume size in GB"},
+    {Name: "bootstrap_root_volume_type", Type: "string", Description: "[BOOTSTRAP] Root volume type"},
+    {Name: "cluster_name", Type: "string", Description: "Name of the DC/OS cluster"},
+    {Name: "cluster_name_random_string", Type: "string", Description: "Add a random string to the cluster name"},
+    {Name: "custom_dcos_download_path", Type: "string", Description: "insert location of dcos installer script (optional)"},
+    {Name: "dcos_adminrouter_tls_1_0_enabled", Type: "string", Description: "Indicates whether to enable TLSv1 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_1_1_enabled", Type: "string", Description: "Indicates whether to enable TLSv1.1 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_1_2_enabled", Type: "string", Description: "Indicates whether to enable TLSv1.2 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_cipher_suite", Type: "string", Description: "[Enterprise DC/OS] Indicates whether to allow web browsers to send the DC/OS authentication cookie through a non-HTTPS connection. (optional)"},
+    {Name: "dcos_agent_list", Type: "string", Description: "used to list the agents in the config.yaml (optional)"},
+    {Name: "dcos_audit_logging", Type: "string", Description: "[Enterprise DC/OS] enable security decisions are logged for Mesos, Marathon, and Jobs. (optional)"},
+    {Name: "dcos_auth_cookie_secure_flag", Type: "string", Description: "[Enterprise DC/OS] allow web browsers to send the DC/OS authentication cookie through a non-HTTPS connection. (optional)"},
+    {Name: "dcos_aws_access_key_id", Type: "string", Description: "AWS key ID for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_region", Type: "string", Description: "AWS region for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_secret_access_key", Type: "string", Description: "AWS secret key for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_template_storage_access_key_id", Type: "string", Description: "AWS key ID for CloudFormation template storage (optional)"},
+    {Name: "dcos_aws_template_storage_bucket", Type: "string", Description: "AWS CloudFormation bucket name (optional)"},
+    {Name: "dcos_aws_template_storage_bucket_path", Type: "string", Description: "AWS CloudFormation bucket path (optional)"},
+    {Name: "dcos_aws_template_storage_region_name", Type: "string", Description: "AWS CloudFormation region name (optional)"},
+    {Name: "dcos_aws_template_storage_secret_access_key", Type: "string", Description: "AWS secret key for the CloudFormation template (optional)"},
+    {Name: "dcos_aws_template_upload", Type: "string", Description: "To automatically upload the customized advanced templates to your S3 bucket. (optional)"},
+    {Name: "dcos_bootstrap_port", Type: "string", Description: "Port of the bootstrap URL"},
+    {Name: "dcos_bouncer_expiration_auth_token_days", Type: "string", Description: "[Enterprise DC/OS] Sets the auth token time-to-live (TTL) for Identity and Access Management. (optional)"},
+    {Name: "dcos_ca_certificate_chain_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing the complete CA certification chain required for end-entity certificate verification, in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_ca_certificate_key_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing a single X.509 certificate private key in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_ca_certificate_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing a single X.509 CA certificate in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_calico_ipinip_mtu", Type: "string", Description: "The MTU to set on the Calico IPIP tunnel device. (optional)"},
+    {Name: "dcos_calico_network_cidr", Type: "string", Description: "Subnet allocated for calico"},
+    {Name: "dcos_calico_veth_mtu", Type: "string", Description: "The MTU to set on the veth pair devices. (optional)"},
+    {Name: "dcos_calico_vxlan_enabled", Type: "string", Description: "Control whether IP-in-IP or VXLAN mode is used for calico. (optional)"},
+    {Name: "dcos_calico_vxlan_mtu", Type: "string", Description: "The MTU to set on the Calico VXLAN tunnel device. (optional)"},
+    {Name: "dcos_calico_vxlan_port", Type: "string", Description: "The UDP port used for calico VXLAN. This configuration works when dcos_calico_vxlan_enabled is set to be true. (optional)"},
+    {Name: "dcos_calico_vxlan_vni", Type: "string", Description: "The virtual network ID used for calico VXLAN. (optional)"},
+    {Name: "dcos_check_time", Type: "string", Description: "Check if Network Time Protocol (NTP) is enabled during DC/OS startup. (optional)"},
+    {Name: "dcos_cluster_docker_credentials", Type: "string", Description: "Dictionary of Docker credentials to pass. (optional)"},
+    {Name: "dcos_cluster_docker_credentials_dcos_owned", Type: "string", Description: "Indicates whether to store the credentials file in /opt/mesosphere or /etc/mesosphere/docker_credentials. A sysadmin cannot edit /opt/mesosphere directly (optional)"},
+    {Name: "dcos_cluster_docker_credentials_enabled", Type: "string", Description: "Indicates whether to pass the Mesos --docker_config option to Mesos. (optional)"},
+    {Name: "dcos_cluster_docker_credentials_write_to_etc", Type: "string", Description: "Indicates whether to write a cluster credentials file. (optional)"},
+    {Name: "dcos_cluster_docker_registry_enabled", Type: "string", Description: "DC/OS cluster docker registry enabled"},
+    {Name: "dcos_cluster_docker_registry_url", Type: "string", Description: "The custom URL that Mesos uses to pull Docker images from. If set, it will configure the Mesos --docker_registry flag to the specified URL. (optional)"},
+    {Name: "dcos_cluster_name", Type: "string", Description: "sets the DC/OS cluster name"},
+    {Name: "dcos_config", Type: "string", Description: "used to add any extra arguments in the config.yaml that are not specified here. (optional)"},
+    {Name: "dcos_custom_checks", Type: "string", Description: "Custom installation checks that are added to the default check configuration process. (optional)"},
+    {Name: "dcos_customer_key", Type: "string", Description: "[Enterprise DC/OS] sets the customer key (optional)"},
+    {Name: "dcos_dns_bind_ip_blacklist", Type: "string", Description: "A list of IP addresses that DC/OS DNS resolvers cannot bind to. (optional)"},
+    {Name: "dcos_dns_forward_zones", Type: "string", Description: "Allow to forward DNS to certain domain requests to specific server. The following syntax must be used in combination with Terraform string heredoc. (optional) (:warning: DC/OS 1.10+)"},
+    {Name: "dcos_dns_search", Type: "string", Description: "A space-separated list of domains that are tried when an unqualified domain is entered. (optional)"},
+    {Name: "dcos_docker_remove_delay", Type: "string", Description: "The amount of time to wait before removing stale Docker images stored on the agent nodes and the Docker image generated by the installer. (optional)"},
+    {Name: "dcos_download_url_checksum", Type: "string", Description: "Custom DC/OS download URL SHA256 Checksum. Empty string omits checking."},
+    {Name: "dcos_enable_docker_gc", Type: "string", Description: "Indicates whether to run the docker-gc script, a simple Docker container and image garbage collection script, once every hour to clean up stray Docker containers. (optional)"},
+    {Name: "dcos_enable_gpu_isolation", Type: "string", Description: "Indicates whether to enable GPU support in DC/OS. (optional)"},
+    {Name: "dcos_enable_mesos_input_plugin", Type: "string", Description: "Indicates whether to enable Telegraf's Mesos input plugin to collect Mesos metrics from Mesos masters and agents. Options: `true` or `false` (optional)"},
+    {Name: "dcos_exhibitor_address", Type: "string", Description: "The address of the load balancer in front of the masters (recommended)"},
+    {Name: "dcos_exhibitor_azure_account_key", Type: "string", Description: "the azure account key for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_azure_account_name", Type: "string", Description: "the azure account name for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_azure_prefix", Type: "string", Description: "the azure account name for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_explicit_keys", Type: "string", Description: "set whether you are using AWS API keys to grant Exhibitor access to S3. (optional)"},
+    {Name: "dcos_exhibitor_storage_backend", Type: "string", Description: "options are static, aws_s3, azure, or zookeeper (recommended)"},
+    {Name: "dcos_exhibitor_zk_hosts", Type: "string", Description: "a comma-separated list of one or more ZooKeeper node IP and port addresses to use for configuring the internal Exhibitor instances. (not recommended but required with exhibitor_storage_backend set to ZooKeeper. Use aws_s3 or azure instead. Assumes external ZooKeeper is already online.)"},
+    {Name: "dcos_exhibitor_zk_path", Type: "string", Description: "the filepath that Exhibitor uses to store data (not recommended but required with exhibitor_storage_backend set to zookeeper. Use aws_s3 or azureinstead. Assumes external ZooKeeper is already online.)"},
+    {Name: "dcos_fault_domain_detect_contents", Type: "string", Description: "[Enterprise DC/OS] fault domain script contents. Optional but required if no fault-domain-detect script present."},
+    {Name: "dcos_fault_domain_enabled", Type: "string", Description: "[Enterprise DC/OS] used to control if fault domain is enabled"},
+    {Name: "dcos_gc_delay", Type: "string", Description: "The maximum amount of time to wait before cleaning up the executor directories (optional)"},
+    {Name: "dcos_gpus_are_scarce", Type: "string", Description: "Indicates whether to treat GPUs as a scarce resource in the cluster. (optional)"},
+    {Name: "dcos_http_proxy", Type: "string", Description: "http proxy (optional)"},
+    {Name: "dcos_https_proxy", Type: "string", Description: "https proxy (optional)"},
+    {Name: "dcos_image_commit", Type: "string", Description: "The commit hash for the build of DC/OS"},
+    {Name: "dcos_instance_os", Type: "string", Description: "Operating system to use. Instead of using your own AMI you could use a provided OS."},
+    {Name: "dcos_ip_detect_contents", Type: "string", Description: "Allows DC/OS to detect your private address. Use this to pass this as an input to the module rather than a file in side your bootstrap node. (recommended)"},
+    {Name: "dcos_ip_detect_public_contents", Type: "string", Description: "Allows DC/OS to be aware of your publicly routeable address for ease of use (recommended)"},
+    {Name: "dcos_ip_detect_public_filename", Type: "string", Description: "statically set your detect-ip-public path"},
+    {Name: "dcos_l4lb_enable_ipv6", Type: "string", Description: "A boolean that indicates if layer 4 load balancing is available for IPv6 networks. (optional)"},
+    {Name: "dcos_license_key_contents", Type: "string", Description: "[Enterprise DC/OS] used to privide the license key of DC/OS for Enterprise Edition. Optional if license.txt is present on bootstrap node."},
+    {Name: "dcos_log_directory", Type: "string", Description: "The path to the installer host logs from the SSH processes. (optional)"},
+    {Name: "dcos_master_discovery", Type: "string", Description: "The Mesos master discovery method. The available options are static or master_http_loadbalancer. (recommend the use of master_http_loadbalancer)"},
+    {Name: "dcos_master_dns_bindall", Type: "string", Description: "Indicates whether the master DNS port is open. (optional)"},
+    {Name: "dcos_master_external_loadbalancer", Type: "string", Description: "Allows DC/OS to configure certs around the External Load Balancer name. If not used SSL verfication issues will arrise. EE only. (recommended)"},
+    {Name: "dcos_master_list", Type: "string", Description: "statically set your master nodes (not recommended but required with exhibitor_storage_backend set to static. Use aws_s3 or azure instead, that way you can replace masters in the cloud.)"},
+    {Name: "dcos_mesos_container_log_sink", Type: "string", Description: "The log manager for containers (tasks). The options are to send logs to: 'journald', 'logrotate', 'journald+logrotate'. (optional)"},
+    {Name: "dcos_mesos_dns_set_truncate_bit", Type: "string", Description: "Indicates whether to set the truncate bit if the response is too large to fit in a single packet. (optional)"},
+    {Name: "dcos_mesos_max_completed_tasks_per_framework", Type: "string", Description: "The number of completed tasks for each framework that the Mesos master will retain in memory. (optional)"},
+    {Name: "dcos_no_proxy", Type: "string", Description: "A YAML nested list (-) of addresses to exclude from the proxy. (optional)"},
+    {Name: "dcos_num_masters", Type: "string", Description: "set the num of master nodes (required with exhibitor_storage_backend set to aws_s3, azure, ZooKeeper)"},
+    {Name: "dcos_oauth_enabled", Type: "string", Description: "[Open DC/OS Only] Indicates whether to enable authentication for your cluster. (optional)"},
+    {Name: "dcos_overlay_config_attempts", Type: "string", Description: "Specifies how many failed configuration attempts are allowed before the overlay configuration modules stop trying to configure an virtual network. (optional)"},
+    {Name: "dcos_overlay_enable", Type: "string", Description: "Enable to disable overlay (optional)"},
+    {Name: "dcos_overlay_mtu", Type: "string", Description: "The maximum transmission unit (MTU) of the Virtual Ethernet (vEth) on the containers that are launched on the overlay. (optional)"},
+    {Name: "dcos_overlay_network", Type: "string", Description: "This group of parameters define an virtual network for DC/OS. (optional)"},
+    {Name: "dcos_package_storage_uri", Type: "string", Description: "Where to permanently store DC/OS packages. The value must be a file URL. (optional)"},
+    {Name: "dcos_previous_version", Type: "string", Description: "DC/OS 1.9+ requires users to set this value to ensure users know the version. Terraform helps populate this value, but users can override it here. (recommended)"},
+    {Name: "dcos_previous_version_master_index", Type: "string", Description: "Used to track the index of master for quering the previous DC/OS version during upgrading. (optional) applicable: 1.9+"},
+    {Name: "dcos_process_timeout", Type: "string", Description: "The allowable amount of time, in seconds, for an action to begin after the process forks. (optional)"},
+    {Name: "dcos_public_agent_list", Type: "string", Description: "statically set your public agents (not recommended)"},
+    {Name: "dcos_resolvers", Type: "string", Description: "A YAML nested list (-) of DNS resolvers for your DC/OS cluster nodes. (recommended)"},
+    {Name: "dcos_rexray_config", Type: "string", Description: "The REX-Ray configuration method for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_rexray_config_filename", Type: "string", Description: "The REX-Ray configuration filename for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_rexray_config_method", Type: "string", Description: "The REX-Ray configuration method for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_s3_bucket", Type: "string", Description: "name of the s3 bucket for the exhibitor backend (recommended but required with dcos_exhibitor_address)"},
+    {Name: "dcos_s3_prefix", Type: "string", Description: "name of the s3 prefix for the exhibitor backend (recommended but required with dcos_exhibitor_address)"},
+    {Name: "dcos_security", Type: "string", Description: "[Enterprise DC/OS] set the security level of DC/OS, either 'strict' or 'permissive'. Default is 'permissive'. (recommended)"},
+    {Name: "dcos_skip_checks", Type: "string", Description: "Upgrade option: Used to skip all dcos checks that may block an upgrade if any DC/OS component is unhealthly. (optional) applicable: 1.10+"},
+    {Name: "dcos_staged_package_storage_uri", Type: "string", Description: "Where to temporarily store DC/OS packages while they are being added. (optional)"},
+    {Name: "dcos_superuser_password_hash", Type: "string", Description: "[Enterprise DC/OS] set the superuser password hash (recommended)"},
+    {Name: "dcos_superuser_username", Type: "string", Description: "[Enterprise DC/OS] set the superuser username (recommended)"},
+    {Name: "dcos_telemetry_enabled", Type: "string", Description: "Change the telemetry option (optional)"},
+    {Name: "dcos_ucr_default_bridge_subnet", Type: "string", Description: "IPv4 subnet allocated to the mesos-bridge CNI network for UCR bridge-mode networking. (optional)"},
+    {Name: "dcos_use_proxy", Type: "string", Description: "To enable use of proxy for internal routing (optional)"},
+    {Name: "dcos_variant", Type: "string", Description: "Specifies which DC/OS variant it should be: `open` (Open Source) or `ee` (Enterprise Edition)"},
+    {Name: "dcos_version", Type: "string", Description: "Specifies which DC/OS version instruction to use. Options: 2.0.0, 1.13.6, 1.12.4, 1.11.12, etc. See https://versions.d2iq.com/version for a full list."},
+    {Name: "dcos_versions_service_url", Type: "string", Description: "DC/OS Versions Service allows to identify DC/OS versions"},
+    {Name: "dcos_zk_agent_credentials", Type: "string", Description: "[Enterprise DC/OS] set the ZooKeeper agent credentials (recommended)"},
+    {Name: "dcos_zk_master_credentials", Type: "string", Description: "[Enterprise DC/OS] set the ZooKeeper master credentials (recommended)"},
+    {Name: "dcos_zk_super_credentials", Type: "string", Description: "[Enterprise DC/OS] set the zk super credentials (recommended)"},
+    {Name: "masters_acm_cert_arn", Type: "string", Description: "ACM certifacte to be used for the masters load balancer"},
+    {Name: "masters_associate_public_ip_address", Type: "string", Description: "[MASTERS] Associate a public ip address with there instances"},
+    {Name: "masters_aws_ami", Type: "string", Description: "[MASTERS] AMI to be used"},
+    {Name: "masters_hostname_format", Type: "string", Description: "[MASTERS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "masters_iam_instance_profile", Type: "string", Description: "[MASTERS] Instance profile to be used for these instances"},
+    {Name: "masters_instance_type", Type: "string", Description: "[MASTERS] Instance type"},
+    {Name: "masters_internal_acm_cert_arn", Type: "string", Description: "ACM certifacte to be used for the internal masters load balancer"},
+    {Name: "masters_os", Type: "string", Description: "[MASTERS] Operating system to use. Instead of using your own AMI you could use a provided OS."},
+    {Name: "masters_root_volume_size", Type: "string", Description: "[MASTERS] Root volume size in GB"},
+    {Name: "masters_user_data", Type: "string", Description: "[MASTERS] User data to be used on these instances (cloud-init)"},
+    {Name: "num_masters", Type: "string", Description: "Specify the amount of masters. For redundancy you should have at least 3"},
+    {Name: "num_of_private_agents", Type: "string", Description: "Specify the amount of private agents. These agents will provide your main resources"},
+    {Name: "num_of_public_agents", Type: "string", Description: "Specify the amount of public agents. These agents will host marathon-lb and edgelb"},
+    {Name: "num_private_agents", Type: "string", Description: "Specify the amount of private agents. These agents will provide your main resources"},
+    {Name: "num_public_agents", Type: "string", Description: "Specify the amount of public agents. These agents will host marathon-lb and edgelb"},
+    {Name: "private_agents_associate_public_ip_address", Type: "string", Description: "[PRIVATE AGENTS] Associate a public ip address with there instances"},
+    {Name: "private_agents_aws_ami", Type: "string", Description: "[PRIVATE AGENTS] AMI to be used"},
+    {Name: "private_agents_extra_volumes", Type: "string", Description: "[PRIVATE AGENTS] Extra volumes for each private agent"},
+    {Name: "private_agents_hostname_format", Type: "string", Description: "[PRIVATE AGENTS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "private_agents_iam_instance_profile", Type: "string", Description: "[PRIVATE AGENTS] Instance profile to be used for these instances"},
+    {Name: "private_agents_instance_type", Type: "string", Description: "[PRIVATE AGENTS] Instance type"},
+    {Name: "private_agents_os", Type: "string", Description: "[PRIVATE AGENTS] Operating system to use. Instead of using your own AMI you could use a provided OS."},
+    {Name: "private_agents_root_volume_size", Type: "string", Description: "[PRIVATE AGENTS] Root volume size in GB"},
+    {Name: "private_agents_root_volume_type", Type: "string", Description: "[PRIVATE AGENTS] Root volume type"},
+    {Name: "private_agents_user_data", Type: "string", Description: "[PRIVATE AGENTS] User data to be used on these instances (cloud-init)"},
+    {Name: "public_agents_access_ips", Type: "list", Description: "List of ips allowed access to public agents. admin_ips are joined to this list"},
+    {Name: "public_agents_acm_cert_arn", Type: "string", Description: "ACM certifacte to be used for the public agents load balancer"},
+    {Name: "public_agents_additional_ports", Type: "string", Description: "List of additional ports allowed for public access on public agents (80 and 443 open by default)"},
+    {Name: "public_agents_associate_public_ip_address", Type: "string", Description: "[PUBLIC AGENTS] Associate a public ip address with there instances"},
+    {Name: "public_agents_aws_ami", Type: "string", Description: "[PUBLIC AGENTS] AMI to be used"},
+    {Name: "public_agents_extra_volumes", Type: "string", Description: "[PUBLIC AGENTS] Extra volumes for each public agent"},
+    {Name: "public_agents_hostname_format", Type: "string", Description: "[PUBLIC AGENTS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "public_agents_iam_instance_profile", Type: "string", Description: "[PUBLIC AGENTS] Instance profile to be used for these instances"},
+    {Name: "public_agents_instance_type", Type: "string", Description: "[PUBLIC AGENTS] Instance type"},
+    {Name: "public_agents_os", Type: "string", Description: "[PUBLIC AGENTS] Operating system to use. Instead of using your own AMI you could use a provided OS."},
+    {Name: "public_agents_root_volume_size", Type: "string", Description: "[PUBLIC AGENTS] Root volume size"},
+    {Name: "public_agents_root_volume_type", Type: "string", Description: "[PUBLIC AGENTS] Specify the root volume type."},
+    {Name: "public_agents_user_data", Type: "string", Description: "[PUBLIC AGENTS] User data to be used on these instances (cloud-init)"},
+    {Name: "ssh_public_key", Type: "string", Description: "SSH public key in authorized keys format (e.g. 'ssh-rsa ..') to be used with the instances. Make sure you added this key to your ssh-agent."},
+    {Name: "ssh_public_key_file", Type: "string", Description: "Path to SSH public key. This is mandatory but can be set to an empty string if you want to use ssh_public_key with the key as string."},
+    {Name: "subnet_range", Type: "string", Description: "Private IP space to be used in CIDR format"},
+    {Name: "tags", Type: "map", Description: "Add custom tags to all resources"},
+    {Name: "with_replaceable_masters", Type: "string", Description: "Create S3 bucket for exhibitor and configure DC/OS to use it."},
+  }},
+  {Provider: "azurerm", Version: "0.2", Variables: []ModuleVariable{
+    {Name: "accepted_internal_networks", Type: "list", Description: "Subnet ranges for all internal networks"},
+    {Name: "additional_private_agent_ips", Type: "string", Description: "Additional private agent IPs."},
+    {Name: "additional_public_agent_ips", Type: "string", Description: "Additional public agent IPs."},
+    {Name: "additional_windows_private_agent_ips", Type: "string", Description: "Additional windows private agent IPs"},
+    {Name: "additional_windows_private_agent_os_user", Type: "string", Description: "Additional windows private agent os user to be used for WinRM"},
+    {Name: "additional_windows_private_agent_passwords", Type: "string", Description: "Additional windows private agent passwords to be used for WinRM"},
+    {Name: "admin_ips", Type: "list", Description: "List of CIDR admin IPs"},
+    {Name: "admin_username", Type: "string", Description: "SSH User"},
+    {Name: "ansible_additional_config", Type: "string", Description: "Add additional config options to ansible. This is getting merged with generated defaults. Do not specify `dcos:`"},
+    {Name: "ansible_bundled_container", Type: "string", Description: "Docker container with bundled dcos-ansible and ansible executables"},
+    {Name: "ansible_user", Type: "string", Description: "The Ansible user that is used to run the Ansible Tasks."},
+    {Name: "avset_platform_fault_domain_count", Type: "string", Description: "Availability set platform fault domain count, differs from location to location"},
+    {Name: "bootstrap_disk_size", Type: "string", Description: "[BOOTSTRAP] Disk size (GB)"},
+    {Name: "bootstrap_disk_type", Type: "string", Description: "[BOOTSTRAP] Disk type"},
+    {Name: "bootstrap_hostname_format", Type: "string", Description: "[BOOTSTRAP] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "bootstrap_private_ip", Type: "string", Description: "Private IP bootstrap nginx is listening on. Used to build the bootstrap URL."},
+    {Name: "bootstrap_vm_size", Type: "string", Description: "[BOOTSTRAP] Azure virtual machine size"},
+    {Name: "cluster_name", Type: "string", Description: "Name of the DC/OS cluster"},
+    {Name: "cluster_name_random_string", Type: "string", Description: "Add a random string to the cluster name"},
+    {Name: "custom_dcos_download_path", Type: "string", Description: "insert location of dcos installer script (optional)"},
+    {Name: "dcos_adminrouter_tls_1_0_enabled", Type: "string", Description: "Indicates whether to enable TLSv1 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_1_1_enabled", Type: "string", Description: "Indicates whether to enable TLSv1.1 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_1_2_enabled", Type: "string", Description: "Indicates whether to enable TLSv1.2 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_cipher_suite", Type: "string", Description: "[Enterprise DC/OS] Indicates whether to allow web browsers to send the DC/OS authentication cookie through a non-HTTPS connection. (optional)"},
+    {Name: "dcos_agent_list", Type: "string", Description: "used to list the agents in the config.yaml (optional)"},
+    {Name: "dcos_audit_logging", Type: "string", Description: "[Enterprise DC/OS] enable security decisions are logged for Mesos, Marathon, and Jobs. (optional)"},
+    {Name: "dcos_auth_cookie_secure_flag", Type: "string", Description: "[Enterprise DC/OS] allow web browsers to send the DC/OS authentication cookie through a non-HTTPS connection. (optional)"},
+    {Name: "dcos_aws_access_key_id", Type: "string", Description: "AWS key ID for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_region", Type: "string", Description: "AWS region for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_secret_access_key", Type: "string", Description: "AWS secret key for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_template_storage_access_key_id", Type: "string", Description: "AWS key ID for CloudFormation template storage (optional)"},
+    {Name: "dcos_aws_template_storage_bucket", Type: "string", Description: "AWS CloudFormation bucket name (optional)"},
+    {Name: "dcos_aws_template_storage_bucket_path", Type: "string", Description: "AWS CloudFormation bucket path (optional)"},
+    {Name: "dcos_aws_template_storage_region_name", Type: "string", Description: "AWS CloudFormation region name (optional)"},
+    {Name: "dcos_aws_template_storage_secret_access_key", Type: "string", Description: "AWS secret key for the CloudFormation template (optional)"},
+    {Name: "dcos_aws_template_upload", Type: "string", Description: "To automatically upload the customized advanced templates to your S3 bucket. (optional)"},
+    {Name: "dcos_bootstrap_port", Type: "string", Description: "Port of the bootstrap URL"},
+    {Name: "dcos_bouncer_expiration_auth_token_days", Type: "string", Description: "[Enterprise DC/OS] Sets the auth token time-to-live (TTL) for Identity and Access Management. (optional)"},
+    {Name: "dcos_ca_certificate_chain_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing the complete CA certification chain required for end-entity certificate verification, in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_ca_certificate_key_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing a single X.509 certificate private key in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_ca_certificate_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing a single X.509 CA certificate in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_calico_ipinip_mtu", Type: "string", Description: "The MTU to set on the Calico IPIP tunnel device. (optional)"},
+    {Name: "dcos_calico_network_cidr", Type: "string", Description: "Subnet allocated for calico"},
+    {Name: "dcos_calico_veth_mtu", Type: "string", Description: "The MTU to set on the veth pair devices. (optional)"},
+    {Name: "dcos_calico_vxlan_enabled", Type: "string", Description: "Control whether IP-in-IP or VXLAN mode is used for calico. (optional)"},
+    {Name: "dcos_calico_vxlan_mtu", Type: "string", Description: "The MTU to set on the Calico VXLAN tunnel device. (optional)"},
+    {Name: "dcos_calico_vxlan_port", Type: "string", Description: "The UDP port used for calico VXLAN. This configuration works when dcos_calico_vxlan_enabled is set to be true. (optional)"},
+    {Name: "dcos_calico_vxlan_vni", Type: "string", Description: "The virtual network ID used for calico VXLAN. (optional)"},
+    {Name: "dcos_check_time", Type: "string", Description: "Check if Network Time Protocol (NTP) is enabled during DC/OS startup. (optional)"},
+    {Name: "dcos_cluster_docker_credentials", Type: "string", Description: "Dictionary of Docker credentials to pass. (optional)"},
+    {Name: "dcos_cluster_docker_credentials_dcos_owned", Type: "string", Description: "Indicates whether to store the credentials file in /opt/mesosphere or /etc/mesosphere/docker_credentials. A sysadmin cannot edit /opt/mesosphere directly (optional)"},
+    {Name: "dcos_cluster_docker_credentials_enabled", Type: "string", Description: "Indicates whether to pass the Mesos --docker_config option to Mesos. (optional)"},
+    {Name: "dcos_cluster_docker_credentials_write_to_etc", Type: "string", Description: "Indicates whether to write a cluster credentials file. (optional)"},
+    {Name: "dcos_cluster_docker_registry_enabled", Type: "string", Description: "DC/OS cluster docker registry enabled"},
+    {Name: "dcos_cluster_docker_registry_url", Type: "string", Description: "The custom URL that Mesos uses to pull Docker images from. If set, it will configure the Mesos --docker_registry flag to the specified URL. (optional)"},
+    {Name: "dcos_cluster_name", Type: "string", Description: "sets the DC/OS cluster name"},
+    {Name: "dcos_config", Type: "string", Description: "used to add any extra arguments in the config.yaml that are not specified here. (optional)"},
+    {Name: "dcos_custom_checks", Type: "string", Description: "Custom installation checks that are added to the default check configuration process. (optional)"},
+    {Name: "dcos_customer_key", Type: "string", Description: "[Enterprise DC/OS] sets the customer key (optional)"},
+    {Name: "dcos_dns_bind_ip_blacklist", Type: "string", Description: "A list of IP addresses that DC/OS DNS resolvers cannot bind to. (optional)"},
+    {Name: "dcos_dns_forward_zones", Type: "string", Description: "Allow to forward DNS to certain domain requests to specific server. The following syntax must be used in combination with Terraform string heredoc. (optional) (:warning: DC/OS 1.10+)"},
+    {Name: "dcos_dns_search", Type: "string", Description: "A space-separated list of domains that are tried when an unqualified domain is entered. (optional)"},
+    {Name: "dcos_docker_remove_delay", Type: "string", Description: "The amount of time to wait before removing stale Docker images stored on the agent nodes and the Docker image generated by the installer. (optional)"},
+    {Name: "dcos_download_url_checksum", Type: "string", Description: "Custom DC/OS download URL SHA256 Checksum. Empty string omits checking."},
+    {Name: "dcos_enable_docker_gc", Type: "string", Description: "Indicates whether to run the docker-gc script, a simple Docker container and image garbage collection script, once every hour to clean up stray Docker containers. (optional)"},
+    {Name: "dcos_enable_gpu_isolation", Type: "string", Description: "Indicates whether to enable GPU support in DC/OS. (optional)"},
+    {Name: "dcos_enable_mesos_input_plugin", Type: "string", Description: "Indicates whether to enable Telegraf's Mesos input plugin to collect Mesos metrics from Mesos masters and agents. Options: `true` or `false` (optional)"},
+    {Name: "dcos_exhibitor_address", Type: "string", Description: "The address of the load balancer in front of the masters (recommended)"},
+    {Name: "dcos_exhibitor_azure_account_key", Type: "string", Description: "the azure account key for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_azure_account_name", Type: "string", Description: "the azure account name for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_azure_prefix", Type: "string", Description: "the azure account name for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_explicit_keys", Type: "string", Description: "set whether you are using AWS API keys to grant Exhibitor access to S3. (optional)"},
+    {Name: "dcos_exhibitor_storage_backend", Type: "string", Description: "options are static, aws_s3, azure, or zookeeper (recommended)"},
+    {Name: "dcos_exhibitor_zk_hosts", Type: "string", Description: "a comma-separated list of one or more ZooKeeper node IP and port addresses to use for configuring the internal Exhibitor instances. (not recommended but required with exhibitor_storage_backend set to ZooKeeper. Use aws_s3 or azure instead. Assumes external ZooKeeper is already online.)"},
+    {Name: "dcos_exhibitor_zk_path", Type: "string", Description: "the filepath that Exhibitor uses to store data (not recommended but required with exhibitor_storage_backend set to zookeeper. Use aws_s3 or azureinstead. Assumes external ZooKeeper is already online.)"},
+    {Name: "dcos_fault_domain_detect_contents", Type: "string", Description: "[Enterprise DC/OS] fault domain script contents. Optional but required if no fault-domain-detect script present."},
+    {Name: "dcos_fault_domain_enabled", Type: "string", Description: "[Enterprise DC/OS] used to control if fault domain is enabled"},
+    {Name: "dcos_gc_delay", Type: "string", Description: "The maximum amount of time to wait before cleaning up the executor directories (optional)"},
+    {Name: "dcos_gpus_are_scarce", Type: "string", Description: "Indicates whether to treat GPUs as a scarce resource in the cluster. (optional)"},
+    {Name: "dcos_http_proxy", Type: "string", Description: "http proxy (optional)"},
+    {Name: "dcos_https_proxy", Type: "string", Description: "https proxy (optional)"},
+    {Name: "dcos_image_commit", Type: "string", Description: "The commit hash for the build of DC/OS"},
+    {Name: "dcos_instance_os", Type: "string", Description: "Operating system to use. Instead of using your own AMI you could use a provided OS."},
+    {Name: "dcos_ip_detect_contents", Type: "string", Description: "Allows DC/OS to detect your private address. Use this to pass this as an input to the module rather than a file in side your bootstrap node. (recommended)"},
+    {Name: "dcos_ip_detect_public_contents", Type: "string", Description: "Allows DC/OS to be aware of your publicly routeable address for ease of use (recommended)"},
+    {Name: "dcos_ip_detect_public_filename", Type: "string", Description: "statically set your detect-ip-public path"},
+    {Name: "dcos_l4lb_enable_ipv6", Type: "string", Description: "A boolean that indicates if layer 4 load balancing is available for IPv6 networks. (optional)"},
+    {Name: "dcos_license_key_contents", Type: "string", Description: "[Enterprise DC/OS] used to privide the license key of DC/OS for Enterprise Edition. Optional if license.txt is present on bootstrap node."},
+    {Name: "dcos_log_directory", Type: "string", Description: "The path to the installer host logs from the SSH processes. (optional)"},
+    {Name: "dcos_master_discovery", Type: "string", Description: "The Mesos master discovery method. The available options are static or master_http_loadbalancer. (recommend the use of master_http_loadbalancer)"},
+    {Name: "dcos_master_dns_bindall", Type: "string", Description: "Indicates whether the master DNS port is open. (optional)"},
+    {Name: "dcos_master_external_loadbalancer", Type: "string", Description: "Allows DC/OS to configure certs around the External Load Balancer name. If not used SSL verfication issues will arrise. EE only. (recommended)"},
+    {Name: "dcos_master_list", Type: "string", Description: "statically set your master nodes (not recommended but required with exhibitor_storage_backend set to static. Use aws_s3 or azure instead, that way you can replace masters in the cloud.)"},
+    {Name: "dcos_mesos_container_log_sink", Type: "string", Description: "The log manager for containers (tasks). The options are to send logs to: 'journald', 'logrotate', 'journald+logrotate'. (optional)"},
+    {Name: "dcos_mesos_dns_set_truncate_bit", Type: "string", Description: "Indicates whether to set the truncate bit if the response is too large to fit in a single packet. (optional)"},
+    {Name: "dcos_mesos_max_completed_tasks_per_framework", Type: "string", Description: "The number of completed tasks for each framework that the Mesos master will retain in memory. (optional)"},
+    {Name: "dcos_no_proxy", Type: "string", Description: "A YAML nested list (-) of addresses to exclude from the proxy. (optional)"},
+    {Name: "dcos_num_masters", Type: "string", Description: "set the num of master nodes (required with exhibitor_storage_backend set to aws_s3, azure, ZooKeeper)"},
+    {Name: "dcos_oauth_enabled", Type: "string", Description: "[Open DC/OS Only] Indicates whether to enable authentication for your cluster. (optional)"},
+    {Name: "dcos_overlay_config_attempts", Type: "string", Description: "Specifies how many failed configuration attempts are allowed before the overlay configuration modules stop trying to configure an virtual network. (optional)"},
+    {Name: "dcos_overlay_enable", Type: "string", Description: "Enable to disable overlay (optional)"},
+    {Name: "dcos_overlay_mtu", Type: "string", Description: "The maximum transmission unit (MTU) of the Virtual Ethernet (vEth) on the containers that are launched on the overlay. (optional)"},
+    {Name: "dcos_overlay_network", Type: "string", Description: "This group of parameters define an virtual network for DC/OS. (optional)"},
+    {Name: "dcos_package_storage_uri", Type: "string", Description: "Where to permanently store DC/OS packages. The value must be a file URL. (optional)"},
+    {Name: "dcos_previous_version", Type: "string", Description: "DC/OS 1.9+ requires users to set this value to ensure users know the version. Terraform helps populate this value, but users can override it here. (recommended)"},
+    {Name: "dcos_previous_version_master_index", Type: "string", Description: "Used to track the index of master for quering the previous DC/OS version during upgrading. (optional) applicable: 1.9+"},
+    {Name: "dcos_process_timeout", Type: "string", Description: "The allowable amount of time, in seconds, for an action to begin after the process forks. (optional)"},
+    {Name: "dcos_public_agent_list", Type: "string", Description: "statically set your public agents (not recommended)"},
+    {Name: "dcos_resolvers", Type: "string", Description: "A YAML nested list (-) of DNS resolvers for your DC/OS cluster nodes. (recommended)"},
+    {Name: "dcos_rexray_config", Type: "string", Description: "The REX-Ray configuration method for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_rexray_config_filename", Type: "string", Description: "The REX-Ray configuration filename for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_rexray_config_method", Type: "string", Description: "The REX-Ray configuration method for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_s3_bucket", Type: "string", Description: "name of the s3 bucket for the exhibitor backend (recommended but required with dcos_exhibitor_address)"},
+    {Name: "dcos_s3_prefix", Type: "string", Description: "name of the s3 prefix for the exhibitor backend (recommended but required with dcos_exhibitor_address)"},
+    {Name: "dcos_security", Type: "string", Description: "[Enterprise DC/OS] set the security level of DC/OS, either 'strict' or 'permissive'. Default is 'permissive'. (recommended)"},
+    {Name: "dcos_skip_checks", Type: "string", Description: "Upgrade option: Used to skip all dcos checks that may block an upgrade if any DC/OS component is unhealthly. (optional) applicable: 1.10+"},
+    {Name: "dcos_staged_package_storage_uri", Type: "string", Description: "Where to temporarily store DC/OS packages while they are being added. (optional)"},
+    {Name: "dcos_superuser_password_hash", Type: "string", Description: "[Enterprise DC/OS] set the superuser password hash (recommended)"},
+    {Name: "dcos_superuser_username", Type: "string", Description: "[Enterprise DC/OS] set the superuser username (recommended)"},
+    {Name: "dcos_telemetry_enabled", Type: "string", Description: "Change the telemetry option (optional)"},
+    {Name: "dcos_ucr_default_bridge_subnet", Type: "string", Description: "IPv4 subnet allocated to the mesos-bridge CNI network for UCR bridge-mode networking. (optional)"},
+    {Name: "dcos_use_proxy", Type: "string", Description: "To enable use of proxy for internal routing (optional)"},
+    {Name: "dcos_variant", Type: "string", Description: "Specifies which DC/OS variant it should be: `open` (Open Source) or `ee` (Enterprise Edition)"},
+    {Name: "dcos_version", Type: "string", Description: "Specifies which DC/OS version instruction to use. Options: 2.0.0, 1.13.6, 1.12.4, 1.11.12, etc. See https://versions.d2iq.com/version for a full list."},
+    {Name: "dcos_versions_service_url", Type: "string", Description: "DC/OS Versions Service allows to identify DC/OS versions"},
+    {Name: "dcos_zk_agent_credentials", Type: "string", Description: "[Enterprise DC/OS] set the ZooKeeper agent credentials (recommended)"},
+    {Name: "dcos_zk_master_credentials", Type: "string", Description: "[Enterprise DC/OS] set the ZooKeeper master credentials (recommended)"},
+    {Name: "dcos_zk_super_credentials", Type: "string", Description: "[Enterprise DC/OS] set the zk super credentials (recommended)"},
+    {Name: "image", Type: "map", Description: "Source image to boot from. We assume the user has already take care of the prereqs for the image"},
+    {Name: "location", Type: "string", Description: "Azure Region"},
+    {Name: "masters_disk_size", Type: "string", Description: "[MASTERS] Disk size (GB)"},
+    {Name: "masters_disk_type", Type: "string", Description: "[MASTERS] Disk type"},
+    {Name: "masters_hostname_format", Type: "string", Description: "[MASTERS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "masters_vm_size", Type: "string", Description: "[MASTERS] Azure virtual machine size"},
+    {Name: "num_masters", Type: "string", Description: "Specify the amount of masters. For redundancy you should have at least 3"},
+    {Name: "num_private_agents", Type: "string", Description: "Specify the amount of private agents. These agents will provide your main resources"},
+    {Name: "num_public_agents", Type: "string", Description: "Specify the amount of public agents. These agents will host marathon-lb and edgelb"},
+    {Name: "private_agents_disk_size", Type: "string", Description: "[PRIVATE AGENTS] Disk size (GB)"},
+    {Name: "private_agents_disk_type", Type: "string", Description: "[PRIVATE AGENTS] Disk type"},
+    {Name: "private_agents_hostname_format", Type: "string", Description: "[PRIVATE AGENTS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "private_agents_vm_size", Type: "string", Description: "[PRIVATE AGENTS] Azure virtual machine size"},
+    {Name: "public_agents_additional_ports", Type: "string", Description: "List of additional ports allowed for public access on public agents (80 and 443 open by default)"},
+    {Name: "public_agents_disk_size", Type: "string", Description: "[PUBLIC AGENTS] Disk size (GB)"},
+    {Name: "public_agents_disk_type", Type: "string", Description: "[PUBLIC AGENTS] Disk type"},
+    {Name: "public_agents_hostname_format", Type: "string", Description: "[PUBLIC AGENTS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "public_agents_vm_size", Type: "string", Description: "[PUBLIC AGENTS] Azure virtual machine size"},
+    {Name: "ssh_public_key", Type: "string", Description: "SSH public key in authorized keys format (e.g. 'ssh-rsa ..') to be used with the instances. Make sure you added this key to your ssh-agent."},
+    {Name: "ssh_public_key_file", Type: "string", Description: "Path to SSH public key. This is mandatory but can be set to an empty string if you want to use ssh_public_key with the key as string."},
+    {Name: "subnet_range", Type: "string", Description: "Private IP space to be used in CIDR format"},
+    {Name: "tags", Type: "map", Description: "Add custom tags to all resources"},
+  }},
+  {Provider: "gcp", Version: "0.2", Variables: []ModuleVariable{
+    {Name: "accepted_internal_networks", Type: "list", Description: "Subnet ranges for all internal networks"},
+    {Name: "additional_private_agent_ips", Type: "string", Description: "Additional private agent IPs."},
+    {Name: "additional_public_agent_ips", Type: "string", Description: "Additional public agent IPs."},
+    {Name: "additional_windows_private_agent_ips", Type: "string", Description: "Additional windows private agent IPs"},
+    {Name: "additional_windows_private_agent_os_user", Type: "string", Description: "Additional windows private agent os user to be used for WinRM"},
+    {Name: "additional_windows_private_agent_passwords", Type: "string", Description: "Additional windows private agent passwords to be used for WinRM"},
+    {Name: "admin_ips", Type: "list", Description: "List of CIDR admin IPs"},
+    {Name: "agent_cidr_range", Type: "string", Description: "[AGENTS] CIDR range of the agents subnet"},
+    {Name: "ansible_additional_config", Type: "string", Description: "Add additional config options to ansible. This is getting merged with generated defaults. Do not specify `dcos:`"},
+    {Name: "ansible_bundled_container", Type: "string", Description: "Docker container with bundled dcos-ansible and ansible executables"},
+    {Name: "ansible_user", Type: "string", Description: "The Ansible user that is used to run the Ansible Tasks."},
+    {Name: "bootstrap_disk_size", Type: "string", Description: "[BOOTSTRAP] Disk size (GB)"},
+    {Name: "bootstrap_disk_type", Type: "string", Description: "[BOOTSTRAP] Disk type. Can be either 'pd-ssd', 'local-ssd', or 'pd-standard'"},
+    {Name: "bootstrap_hostname_format", Type: "string", Description: "[BOOTSTRAP] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "bootstrap_machine_type", Type: "string", Description: "[BOOTSTRAP] Machine type"},
+    {Name: "bootstrap_private_ip", Type: "string", Description: "Private IP bootstrap nginx is listening on. Used to build the bootstrap URL."},
+    {Name: "cluster_name", Type: "string", Description: "Name of the DC/OS cluster"},
+    {Name: "cluster_name_random_string", Type: "string", Description: "Add a random string to the cluster name"},
+    {Name: "custom_dcos_download_path", Type: "string", Description: "insert location of dcos installer script (optional)"},
+    {Name: "dcos_adminrouter_tls_1_0_enabled", Type: "string", Description: "Indicates whether to enable TLSv1 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_1_1_enabled", Type: "string", Description: "Indicates whether to enable TLSv1.1 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_1_2_enabled", Type: "string", Description: "Indicates whether to enable TLSv1.2 support in Admin Router. (optional)"},
+    {Name: "dcos_adminrouter_tls_cipher_suite", Type: "string", Description: "[Enterprise DC/OS] Indicates whether to allow web browsers to send the DC/OS authentication cookie through a non-HTTPS connection. (optional)"},
+    {Name: "dcos_agent_list", Type: "string", Description: "used to list the agents in the config.yaml (optional)"},
+    {Name: "dcos_audit_logging", Type: "string", Description: "[Enterprise DC/OS] enable security decisions are logged for Mesos, Marathon, and Jobs. (optional)"},
+    {Name: "dcos_auth_cookie_secure_flag", Type: "string", Description: "[Enterprise DC/OS] allow web browsers to send the DC/OS authentication cookie through a non-HTTPS connection. (optional)"},
+    {Name: "dcos_aws_access_key_id", Type: "string", Description: "AWS key ID for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_region", Type: "string", Description: "AWS region for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_secret_access_key", Type: "string", Description: "AWS secret key for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_aws_template_storage_access_key_id", Type: "string", Description: "AWS key ID for CloudFormation template storage (optional)"},
+    {Name: "dcos_aws_template_storage_bucket", Type: "string", Description: "AWS CloudFormation bucket name (optional)"},
+    {Name: "dcos_aws_template_storage_bucket_path", Type: "string", Description: "AWS CloudFormation bucket path (optional)"},
+    {Name: "dcos_aws_template_storage_region_name", Type: "string", Description: "AWS CloudFormation region name (optional)"},
+    {Name: "dcos_aws_template_storage_secret_access_key", Type: "string", Description: "AWS secret key for the CloudFormation template (optional)"},
+    {Name: "dcos_aws_template_upload", Type: "string", Description: "To automatically upload the customized advanced templates to your S3 bucket. (optional)"},
+    {Name: "dcos_bootstrap_port", Type: "string", Description: "Port of the bootstrap URL"},
+    {Name: "dcos_bouncer_expiration_auth_token_days", Type: "string", Description: "[Enterprise DC/OS] Sets the auth token time-to-live (TTL) for Identity and Access Management. (optional)"},
+    {Name: "dcos_ca_certificate_chain_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing the complete CA certification chain required for end-entity certificate verification, in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_ca_certificate_key_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing a single X.509 certificate private key in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_ca_certificate_path", Type: "string", Description: "[Enterprise DC/OS] Path (relative to the $DCOS_INSTALL_DIR) to a file containing a single X.509 CA certificate in the OpenSSL PEM format. (optional)"},
+    {Name: "dcos_calico_ipinip_mtu", Type: "string", Description: "The MTU to set on the Calico IPIP tunnel device. (optional)"},
+    {Name: "dcos_calico_network_cidr", Type: "string", Description: "Subnet allocated for calico"},
+    {Name: "dcos_calico_veth_mtu", Type: "string", Description: "The MTU to set on the veth pair devices. (optional)"},
+    {Name: "dcos_calico_vxlan_enabled", Type: "string", Description: "Control whether IP-in-IP or VXLAN mode is used for calico. (optional)"},
+    {Name: "dcos_calico_vxlan_mtu", Type: "string", Description: "The MTU to set on the Calico VXLAN tunnel device. (optional)"},
+    {Name: "dcos_calico_vxlan_port", Type: "string", Description: "The UDP port used for calico VXLAN. This configuration works when dcos_calico_vxlan_enabled is set to be true. (optional)"},
+    {Name: "dcos_calico_vxlan_vni", Type: "string", Description: "The virtual network ID used for calico VXLAN. (optional)"},
+    {Name: "dcos_check_time", Type: "string", Description: "Check if Network Time Protocol (NTP) is enabled during DC/OS startup. (optional)"},
+    {Name: "dcos_cluster_docker_credentials", Type: "string", Description: "Dictionary of Docker credentials to pass. (optional)"},
+    {Name: "dcos_cluster_docker_credentials_dcos_owned", Type: "string", Description: "Indicates whether to store the credentials file in /opt/mesosphere or /etc/mesosphere/docker_credentials. A sysadmin cannot edit /opt/mesosphere directly (optional)"},
+    {Name: "dcos_cluster_docker_credentials_enabled", Type: "string", Description: "Indicates whether to pass the Mesos --docker_config option to Mesos. (optional)"},
+    {Name: "dcos_cluster_docker_credentials_write_to_etc", Type: "string", Description: "Indicates whether to write a cluster credentials file. (optional)"},
+    {Name: "dcos_cluster_docker_registry_enabled", Type: "string", Description: "DC/OS cluster docker registry enabled"},
+    {Name: "dcos_cluster_docker_registry_url", Type: "string", Description: "The custom URL that Mesos uses to pull Docker images from. If set, it will configure the Mesos --docker_registry flag to the specified URL. (optional)"},
+    {Name: "dcos_cluster_name", Type: "string", Description: "sets the DC/OS cluster name"},
+    {Name: "dcos_config", Type: "string", Description: "used to add any extra arguments in the config.yaml that are not specified here. (optional)"},
+    {Name: "dcos_custom_checks", Type: "string", Description: "Custom installation checks that are added to the default check configuration process. (optional)"},
+    {Name: "dcos_customer_key", Type: "string", Description: "[Enterprise DC/OS] sets the customer key (optional)"},
+    {Name: "dcos_dns_bind_ip_blacklist", Type: "string", Description: "A list of IP addresses that DC/OS DNS resolvers cannot bind to. (optional)"},
+    {Name: "dcos_dns_forward_zones", Type: "string", Description: "Allow to forward DNS to certain domain requests to specific server. The following syntax must be used in combination with Terraform string heredoc. (optional) (:warning: DC/OS 1.10+)"},
+    {Name: "dcos_dns_search", Type: "string", Description: "A space-separated list of domains that are tried when an unqualified domain is entered. (optional)"},
+    {Name: "dcos_docker_remove_delay", Type: "string", Description: "The amount of time to wait before removing stale Docker images stored on the agent nodes and the Docker image generated by the installer. (optional)"},
+    {Name: "dcos_download_url_checksum", Type: "string", Description: "Custom DC/OS download URL SHA256 Checksum. Empty string omits checking."},
+    {Name: "dcos_enable_docker_gc", Type: "string", Description: "Indicates whether to run the docker-gc script, a simple Docker container and image garbage collection script, once every hour to clean up stray Docker containers. (optional)"},
+    {Name: "dcos_enable_gpu_isolation", Type: "string", Description: "Indicates whether to enable GPU support in DC/OS. (optional)"},
+    {Name: "dcos_enable_mesos_input_plugin", Type: "string", Description: "Indicates whether to enable Telegraf's Mesos input plugin to collect Mesos metrics from Mesos masters and agents. Options: `true` or `false` (optional)"},
+    {Name: "dcos_exhibitor_address", Type: "string", Description: "The address of the load balancer in front of the masters (recommended)"},
+    {Name: "dcos_exhibitor_azure_account_key", Type: "string", Description: "the azure account key for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_azure_account_name", Type: "string", Description: "the azure account name for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_azure_prefix", Type: "string", Description: "the azure account name for exhibitor storage (optional but required with dcos_exhibitor_address)"},
+    {Name: "dcos_exhibitor_explicit_keys", Type: "string", Description: "set whether you are using AWS API keys to grant Exhibitor access to S3. (optional)"},
+    {Name: "dcos_exhibitor_storage_backend", Type: "string", Description: "options are static, aws_s3, azure, or zookeeper (recommended)"},
+    {Name: "dcos_exhibitor_zk_hosts", Type: "string", Description: "a comma-separated list of one or more ZooKeeper node IP and port addresses to use for configuring the internal Exhibitor instances. (not recommended but required with exhibitor_storage_backend set to ZooKeeper. Use aws_s3 or azure instead. Assumes external ZooKeeper is already online.)"},
+    {Name: "dcos_exhibitor_zk_path", Type: "string", Description: "the filepath that Exhibitor uses to store data (not recommended but required with exhibitor_storage_backend set to zookeeper. Use aws_s3 or azureinstead. Assumes external ZooKeeper is already online.)"},
+    {Name: "dcos_fault_domain_detect_contents", Type: "string", Description: "[Enterprise DC/OS] fault domain script contents. Optional but required if no fault-domain-detect script present."},
+    {Name: "dcos_fault_domain_enabled", Type: "string", Description: "[Enterprise DC/OS] used to control if fault domain is enabled"},
+    {Name: "dcos_gc_delay", Type: "string", Description: "The maximum amount of time to wait before cleaning up the executor directories (optional)"},
+    {Name: "dcos_gpus_are_scarce", Type: "string", Description: "Indicates whether to treat GPUs as a scarce resource in the cluster. (optional)"},
+    {Name: "dcos_http_proxy", Type: "string", Description: "http proxy (optional)"},
+    {Name: "dcos_https_proxy", Type: "string", Description: "https proxy (optional)"},
+    {Name: "dcos_image_commit", Type: "string", Description: "The commit hash for the build of DC/OS"},
+    {Name: "dcos_instance_os", Type: "string", Description: "Operating system to use. Instead of using your own AMI you could use a provided OS."},
+    {Name: "dcos_ip_detect_contents", Type: "string", Description: "Allows DC/OS to detect your private address. Use this to pass this as an input to the module rather than a file in side your bootstrap node. (recommended)"},
+    {Name: "dcos_ip_detect_public_contents", Type: "string", Description: "Allows DC/OS to be aware of your publicly routeable address for ease of use (recommended)"},
+    {Name: "dcos_ip_detect_public_filename", Type: "string", Description: "statically set your detect-ip-public path"},
+    {Name: "dcos_l4lb_enable_ipv6", Type: "string", Description: "A boolean that indicates if layer 4 load balancing is available for IPv6 networks. (optional)"},
+    {Name: "dcos_license_key_contents", Type: "string", Description: "[Enterprise DC/OS] used to privide the license key of DC/OS for Enterprise Edition. Optional if license.txt is present on bootstrap node."},
+    {Name: "dcos_log_directory", Type: "string", Description: "The path to the installer host logs from the SSH processes. (optional)"},
+    {Name: "dcos_master_discovery", Type: "string", Description: "The Mesos master discovery method. The available options are static or master_http_loadbalancer. (recommend the use of master_http_loadbalancer)"},
+    {Name: "dcos_master_dns_bindall", Type: "string", Description: "Indicates whether the master DNS port is open. (optional)"},
+    {Name: "dcos_master_external_loadbalancer", Type: "string", Description: "Allows DC/OS to configure certs around the External Load Balancer name. If not used SSL verfication issues will arrise. EE only. (recommended)"},
+    {Name: "dcos_master_list", Type: "string", Description: "statically set your master nodes (not recommended but required with exhibitor_storage_backend set to static. Use aws_s3 or azure instead, that way you can replace masters in the cloud.)"},
+    {Name: "dcos_mesos_container_log_sink", Type: "string", Description: "The log manager for containers (tasks). The options are to send logs to: 'journald', 'logrotate', 'journald+logrotate'. (optional)"},
+    {Name: "dcos_mesos_dns_set_truncate_bit", Type: "string", Description: "Indicates whether to set the truncate bit if the response is too large to fit in a single packet. (optional)"},
+    {Name: "dcos_mesos_max_completed_tasks_per_framework", Type: "string", Description: "The number of completed tasks for each framework that the Mesos master will retain in memory. (optional)"},
+    {Name: "dcos_no_proxy", Type: "string", Description: "A YAML nested list (-) of addresses to exclude from the proxy. (optional)"},
+    {Name: "dcos_num_masters", Type: "string", Description: "set the num of master nodes (required with exhibitor_storage_backend set to aws_s3, azure, ZooKeeper)"},
+    {Name: "dcos_oauth_enabled", Type: "string", Description: "[Open DC/OS Only] Indicates whether to enable authentication for your cluster. (optional)"},
+    {Name: "dcos_overlay_config_attempts", Type: "string", Description: "Specifies how many failed configuration attempts are allowed before the overlay configuration modules stop trying to configure an virtual network. (optional)"},
+    {Name: "dcos_overlay_enable", Type: "string", Description: "Enable to disable overlay (optional)"},
+    {Name: "dcos_overlay_mtu", Type: "string", Description: "The maximum transmission unit (MTU) of the Virtual Ethernet (vEth) on the containers that are launched on the overlay. (optional)"},
+    {Name: "dcos_overlay_network", Type: "string", Description: "This group of parameters define an virtual network for DC/OS. (optional)"},
+    {Name: "dcos_package_storage_uri", Type: "string", Description: "Where to permanently store DC/OS packages. The value must be a file URL. (optional)"},
+    {Name: "dcos_previous_version", Type: "string", Description: "DC/OS 1.9+ requires users to set this value to ensure users know the version. Terraform helps populate this value, but users can override it here. (recommended)"},
+    {Name: "dcos_previous_version_master_index", Type: "string", Description: "Used to track the index of master for quering the previous DC/OS version during upgrading. (optional) applicable: 1.9+"},
+    {Name: "dcos_process_timeout", Type: "string", Description: "The allowable amount of time, in seconds, for an action to begin after the process forks. (optional)"},
+    {Name: "dcos_public_agent_list", Type: "string", Description: "statically set your public agents (not recommended)"},
+    {Name: "dcos_resolvers", Type: "string", Description: "A YAML nested list (-) of DNS resolvers for your DC/OS cluster nodes. (recommended)"},
+    {Name: "dcos_rexray_config", Type: "string", Description: "The REX-Ray configuration method for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_rexray_config_filename", Type: "string", Description: "The REX-Ray configuration filename for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_rexray_config_method", Type: "string", Description: "The REX-Ray configuration method for enabling external persistent volumes in Marathon. (optional)"},
+    {Name: "dcos_s3_bucket", Type: "string", Description: "name of the s3 bucket for the exhibitor backend (recommended but required with dcos_exhibitor_address)"},
+    {Name: "dcos_s3_prefix", Type: "string", Description: "name of the s3 prefix for the exhibitor backend (recommended but required with dcos_exhibitor_address)"},
+    {Name: "dcos_security", Type: "string", Description: "[Enterprise DC/OS] set the security level of DC/OS, either 'strict' or 'permissive'. Default is 'permissive'. (recommended)"},
+    {Name: "dcos_skip_checks", Type: "string", Description: "Upgrade option: Used to skip all dcos checks that may block an upgrade if any DC/OS component is unhealthly. (optional) applicable: 1.10+"},
+    {Name: "dcos_staged_package_storage_uri", Type: "string", Description: "Where to temporarily store DC/OS packages while they are being added. (optional)"},
+    {Name: "dcos_superuser_password_hash", Type: "string", Description: "[Enterprise DC/OS] set the superuser password hash (recommended)"},
+    {Name: "dcos_superuser_username", Type: "string", Description: "[Enterprise DC/OS] set the superuser username (recommended)"},
+    {Name: "dcos_telemetry_enabled", Type: "string", Description: "Change the telemetry option (optional)"},
+    {Name: "dcos_ucr_default_bridge_subnet", Type: "string", Description: "IPv4 subnet allocated to the mesos-bridge CNI network for UCR bridge-mode networking. (optional)"},
+    {Name: "dcos_use_proxy", Type: "string", Description: "To enable use of proxy for internal routing (optional)"},
+    {Name: "dcos_variant", Type: "string", Description: "Specifies which DC/OS variant it should be: `open` (Open Source) or `ee` (Enterprise Edition)"},
+    {Name: "dcos_version", Type: "string", Description: "Specifies which DC/OS version instruction to use. Options: 2.0.0, 1.13.6, 1.12.4, 1.11.12, etc. See https://versions.d2iq.com/version for a full list."},
+    {Name: "dcos_versions_service_url", Type: "string", Description: "DC/OS Versions Service allows to identify DC/OS versions"},
+    {Name: "dcos_zk_agent_credentials", Type: "string", Description: "[Enterprise DC/OS] set the ZooKeeper agent credentials (recommended)"},
+    {Name: "dcos_zk_master_credentials", Type: "string", Description: "[Enterprise DC/OS] set the ZooKeeper master credentials (recommended)"},
+    {Name: "dcos_zk_super_credentials", Type: "string", Description: "[Enterprise DC/OS] set the zk super credentials (recommended)"},
+    {Name: "image", Type: "string", Description: "Source image to boot from"},
+    {Name: "labels", Type: "map", Description: "Add custom labels to all resources"},
+    {Name: "master_cidr_range", Type: "string", Description: "[MASTERS] CIDR range of the masters subnet"},
+    {Name: "masters_disk_size", Type: "string", Description: "[MASTERS] Disk size (GB)"},
+    {Name: "masters_disk_type", Type: "string", Description: "[MASTERS] Disk type. Can be either 'pd-ssd', 'local-ssd', or 'pd-standard'"},
+    {Name: "masters_hostname_format", Type: "string", Description: "[MASTERS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "masters_machine_type", Type: "string", Description: "[MASTERS] Machine type"},
+    {Name: "num_masters", Type: "string", Description: "Specify the amount of masters. For redundancy you should have at least 3"},
+    {Name: "num_private_agents", Type: "string", Description: "Specify the amount of private agents. These agents will provide your main resources"},
+    {Name: "num_public_agents", Type: "string", Description: "Specify the amount of public agents. These agents will host marathon-lb and edgelb"},
+    {Name: "private_agents_disk_size", Type: "string", Description: "[PRIVATE AGENTS] Disk size (GB)"},
+    {Name: "private_agents_disk_type", Type: "string", Description: "[PRIVATE AGENTS] Disk type. Can be either 'pd-ssd', 'local-ssd', or 'pd-standard'"},
+    {Name: "private_agents_hostname_format", Type: "string", Description: "[PRIVATE AGENTS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "private_agents_machine_type", Type: "string", Description: "[PRIVATE AGENTS] Machine type"},
+    {Name: "public_agents_additional_ports", Type: "string", Description: "List of additional ports allowed for public access on public agents (80 and 443 open by default)"},
+    {Name: "public_agents_disk_size", Type: "string", Description: "[PUBLIC AGENTS] Disk size (GB)"},
+    {Name: "public_agents_disk_type", Type: "string", Description: "[PUBLIC AGENTS] Disk type. Can be either 'pd-ssd', 'local-ssd', or 'pd-standard'"},
+    {Name: "public_agents_hostname_format", Type: "string", Description: "[PUBLIC AGENTS] Format the hostname inputs are index+1, region, cluster_name"},
+    {Name: "public_agents_machine_type", Type: "string", Description: "[PUBLIC AGENTS] Machine type"},
+    {Name: "ssh_public_key", Type: "string", Description: "SSH public key in authorized keys format (e.g. 'ssh-rsa ..') to be used with the instances. Make sure you added this key to your ssh-agent."},
+    {Name: "ssh_public_key_file", Type: "string", Description: "Path to SSH public key. This is mandatory but can be set to an empty string if you want to use ssh_public_key with the key as string."},
+  }},
+}
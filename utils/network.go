@@ -354,7 +354,7 @@ func (stream NetworkStreamChain) EventuallyUnzipTo(prefix string, stripComponent
 
     rc, err := file.Open()
     if err != nil {
-      return fmt.Errorf("unzip failed: cannot open %s for reading: %s", err.Error())
+      return fmt.Errorf("unzip failed: cannot open %s for reading: %s", file.Name, err.Error())
     }
 
     _, err = io.Copy(outFile, rc)
@@ -604,7 +604,7 @@ func (stream NetworkStreamChain) EventuallyUnzipOnlyTo(prefix string, filenames
 
     rc, err := file.Open()
     if err != nil {
-      return fmt.Errorf("unzip failed: cannot open %s for reading: %s", err.Error())
+      return fmt.Errorf("unzip failed: cannot open %s for reading: %s", file.Name, err.Error())
     }
 
     _, err = io.Copy(outFile, rc)
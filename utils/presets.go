@@ -0,0 +1,219 @@
+package utils
+
+import (
+  "bytes"
+  "flag"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+
+  "gopkg.in/yaml.v3"
+)
+
+// The directory with the presets of the project, and of the user
+// configuration directory
+var PresetsDir string = filepath.Join(".wheels", "presets")
+
+type ClusterPreset struct {
+  Name        string                 `yaml:"-"`
+  Source      string                 `yaml:"-"`
+  Description string                 `yaml:"description,omitempty"`
+  Provider    string                 `yaml:"provider,omitempty"`
+  Values      map[string]interface{} `yaml:"values"`
+}
+
+var builtinPresets []ClusterPreset = []ClusterPreset{
+  {Name: "dev", Source: "built-in", Provider: "aws",
+    Description: "Minimal development cluster with 1 master and 1 private agent",
+    Values: map[string]interface{}{
+      "num_masters":        1,
+      "num_private_agents": 1,
+      "num_public_agents":  0,
+    }},
+  {Name: "ha", Source: "built-in", Provider: "aws",
+    Description: "Highly-available production cluster with 3 masters",
+    Values: map[string]interface{}{
+      "num_masters":                  3,
+      "num_private_agents":           3,
+      "num_public_agents":            1,
+      "masters_instance_type":        "m5.xlarge",
+      "private_agents_instance_type": "m5.xlarge",
+      "public_agents_instance_type":  "m5.xlarge",
+    }},
+  {Name: "gpu", Source: "built-in", Provider: "aws",
+    Description: "Cluster with GPU private agents",
+    Values: map[string]interface{}{
+      "num_private_agents":              2,
+      "private_agents_instance_type":    "p3.2xlarge",
+      "private_agents_root_volume_size": 120,
+    }},
+}
+
+/**
+ * Returns the flag values of the preset, in the format used in the
+ * command-line. Lists give one value per item and maps one `key=value`
+ * value per key.
+ */
+func (p *ClusterPreset) FlagValues() map[string][]string {
+  ret := make(map[string][]string)
+  for name, value := range p.Values {
+    switch tv := value.(type) {
+    case []interface{}:
+      for _, item := range tv {
+        ret[name] = append(ret[name], fmt.Sprintf("%v", item))
+      }
+    case map[string]interface{}:
+      var keys []string
+      for k := range tv {
+        keys = append(keys, k)
+      }
+      sort.Strings(keys)
+      for _, k := range keys {
+        ret[name] = append(ret[name], fmt.Sprintf("%s=%v", k, tv[k]))
+      }
+    default:
+      ret[name] = []string{fmt.Sprintf("%v", value)}
+    }
+  }
+  return ret
+}
+
+/**
+ * Sets the flags of the preset that were not given in the command-line, so
+ * the explicit flags always have precedence
+ */
+func (p *ClusterPreset) Apply(flags *flag.FlagSet) error {
+  explicit := make(map[string]bool)
+  flags.Visit(func(f *flag.Flag) {
+    explicit[f.Name] = true
+  })
+
+  values := p.FlagValues()
+  var names []string
+  for name := range values {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  var errs []string
+  for _, name := range names {
+    if flags.Lookup(name) == nil {
+      errs = append(errs, fmt.Sprintf("Unknown parameter '%s'", name))
+      continue
+    }
+    if explicit[name] {
+      continue
+    }
+    for _, value := range values[name] {
+      err := flags.Set(name, value)
+      if err != nil {
+        errs = append(errs, fmt.Sprintf("Could not set %s: %s", name, err.Error()))
+      }
+    }
+  }
+
+  if len(errs) > 0 {
+    return fmt.Errorf("Could not apply preset '%s': %s", p.Name, strings.Join(errs, "; "))
+  }
+  return nil
+}
+
+/**
+ * Loads the presets of the given cloud provider: the built-in ones, then the
+ * ones of the user configuration directory and finally the ones of the
+ * project, each overriding the presets with the same name
+ */
+func LoadClusterPresets(project *ProjectSandbox, provider string) ([]ClusterPreset, error) {
+  presets := make(map[string]ClusterPreset)
+  for _, preset := range builtinPresets {
+    presets[preset.Name] = preset
+  }
+
+  var dirs []string
+  if userDir, err := GetUserConfigDir(); err == nil {
+    dirs = append(dirs, filepath.Join(userDir, "presets"))
+  }
+  dirs = append(dirs, project.GetFilePath(PresetsDir))
+
+  for _, dir := range dirs {
+    loaded, err := readPresetsDir(dir)
+    if err != nil {
+      return nil, err
+    }
+    for _, preset := range loaded {
+      presets[preset.Name] = preset
+    }
+  }
+
+  var ret []ClusterPreset
+  for _, preset := range presets {
+    if preset.Provider == "" || preset.Provider == provider {
+      ret = append(ret, preset)
+    }
+  }
+  sort.Slice(ret, func(i, j int) bool {
+    return ret[i].Name < ret[j].Name
+  })
+  return ret, nil
+}
+
+/**
+ * Returns the preset with the given name, or an error listing the available
+ * presets
+ */
+func FindClusterPreset(presets []ClusterPreset, name string) (*ClusterPreset, error) {
+  var names []string
+  for i, preset := range presets {
+    if preset.Name == name {
+      return &presets[i], nil
+    }
+    names = append(names, preset.Name)
+  }
+  return nil, fmt.Errorf("Could not find preset '%s'. Available presets: %s", name, strings.Join(names, ", "))
+}
+
+/**
+ * Reads the `<name>.yaml` preset files of a directory, if it exists
+ */
+func readPresetsDir(dir string) ([]ClusterPreset, error) {
+  entries, err := ioutil.ReadDir(dir)
+  if err != nil {
+    if os.IsNotExist(err) {
+      return nil, nil
+    }
+    return nil, fmt.Errorf("Could not read %s: %s", dir, err.Error())
+  }
+
+  var presets []ClusterPreset
+  for _, entry := range entries {
+    ext := filepath.Ext(entry.Name())
+    if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+      continue
+    }
+
+    path := filepath.Join(dir, entry.Name())
+    content, err := ioutil.ReadFile(path)
+    if err != nil {
+      return nil, fmt.Errorf("Could not read %s: %s", path, err.Error())
+    }
+
+    preset := ClusterPreset{}
+    if len(bytes.TrimSpace(content)) > 0 {
+      dec := yaml.NewDecoder(bytes.NewReader(content))
+      dec.KnownFields(true)
+      err = dec.Decode(&preset)
+      if err != nil {
+        return nil, fmt.Errorf("Could not parse %s: %s", path, err.Error())
+      }
+    }
+
+    preset.Name = strings.TrimSuffix(entry.Name(), ext)
+    preset.Source = path
+    presets = append(presets, preset)
+  }
+
+  return presets, nil
+}
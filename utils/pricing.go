@@ -0,0 +1,252 @@
+package utils
+
+import (
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "regexp"
+  "strconv"
+  "time"
+
+  "gopkg.in/yaml.v3"
+)
+
+// The name of the file that overrides the built-in prices, in the user
+// configuration directory and in the `.wheels` directory of the project
+var PricingFile string = "pricing.yaml"
+
+/**
+ * The on-demand prices of a cloud region, in USD
+ */
+type RegionPricing struct {
+  // The hourly price of each instance type
+  Instances map[string]float64 `yaml:"instances,omitempty"`
+
+  // The monthly price of a GB of volume storage, per volume type (ex. `gp2`)
+  Volumes map[string]float64 `yaml:"volumes,omitempty"`
+}
+
+type PricingTable struct {
+  Regions map[string]RegionPricing `yaml:"regions"`
+}
+
+// The built-in on-demand AWS prices (Linux, USD). They are only meant for
+// estimates, use a pricing.yaml file to update or extend them.
+var builtinAWSPricing string = `
+regions:
+  us-east-1: &us-east-1
+    instances:
+      t2.medium: 0.0464
+      t2.large: 0.0928
+      t3.medium: 0.0416
+      t3.large: 0.0832
+      t3.xlarge: 0.1664
+      m4.large: 0.10
+      m4.xlarge: 0.20
+      m4.2xlarge: 0.40
+      m5.large: 0.096
+      m5.xlarge: 0.192
+      m5.2xlarge: 0.384
+      m5.4xlarge: 0.768
+      m5.8xlarge: 1.536
+      m5.12xlarge: 2.304
+      c5.large: 0.085
+      c5.xlarge: 0.17
+      c5.2xlarge: 0.34
+      c5.4xlarge: 0.68
+      r5.large: 0.126
+      r5.xlarge: 0.252
+      r5.2xlarge: 0.504
+      r5.4xlarge: 1.008
+      p3.2xlarge: 3.06
+      p3.8xlarge: 12.24
+      g4dn.xlarge: 0.526
+      g4dn.2xlarge: 0.752
+    volumes:
+      gp2: 0.10
+      io1: 0.125
+      st1: 0.045
+      sc1: 0.025
+      standard: 0.05
+  us-east-2: *us-east-1
+  us-west-2: *us-east-1
+  us-west-1:
+    instances:
+      t2.medium: 0.0552
+      t2.large: 0.1104
+      t3.medium: 0.0496
+      t3.large: 0.0992
+      m5.large: 0.112
+      m5.xlarge: 0.224
+      m5.2xlarge: 0.448
+      m5.4xlarge: 0.896
+      c5.large: 0.106
+      c5.xlarge: 0.212
+      c5.2xlarge: 0.424
+      r5.large: 0.148
+      r5.xlarge: 0.296
+      r5.2xlarge: 0.592
+    volumes:
+      gp2: 0.12
+      io1: 0.138
+      standard: 0.08
+  eu-west-1:
+    instances:
+      t2.medium: 0.05
+      t2.large: 0.1008
+      t3.medium: 0.0456
+      t3.large: 0.0912
+      m5.large: 0.107
+      m5.xlarge: 0.214
+      m5.2xlarge: 0.428
+      m5.4xlarge: 0.856
+      c5.large: 0.096
+      c5.xlarge: 0.192
+      c5.2xlarge: 0.384
+      r5.large: 0.141
+      r5.xlarge: 0.282
+      r5.2xlarge: 0.564
+      p3.2xlarge: 3.305
+    volumes:
+      gp2: 0.11
+      io1: 0.138
+      standard: 0.055
+  eu-central-1:
+    instances:
+      t2.medium: 0.0536
+      t2.large: 0.1072
+      t3.medium: 0.048
+      t3.large: 0.096
+      m5.large: 0.115
+      m5.xlarge: 0.23
+      m5.2xlarge: 0.46
+      m5.4xlarge: 0.92
+      c5.large: 0.097
+      c5.xlarge: 0.194
+      c5.2xlarge: 0.388
+      r5.large: 0.152
+      r5.xlarge: 0.304
+      r5.2xlarge: 0.608
+      p3.2xlarge: 3.823
+    volumes:
+      gp2: 0.119
+      io1: 0.149
+      standard: 0.059
+`
+
+/**
+ * Loads the built-in AWS prices, then the ones of the user configuration
+ * directory and finally the ones of the project, each overriding the prices
+ * of the same instance or volume types
+ */
+func LoadAWSPricing(project *ProjectSandbox) (*PricingTable, error) {
+  table := &PricingTable{}
+  err := yaml.Unmarshal([]byte(builtinAWSPricing), table)
+  if err != nil {
+    return nil, fmt.Errorf("Could not parse the built-in prices: %s", err.Error())
+  }
+
+  var paths []string
+  if userDir, err := GetUserConfigDir(); err == nil {
+    paths = append(paths, filepath.Join(userDir, PricingFile))
+  }
+  paths = append(paths, project.GetFilePath(filepath.Join(".wheels", PricingFile)))
+
+  for _, path := range paths {
+    content, err := ioutil.ReadFile(path)
+    if err != nil {
+      if os.IsNotExist(err) {
+        continue
+      }
+      return nil, fmt.Errorf("Could not read %s: %s", path, err.Error())
+    }
+
+    layer := &PricingTable{}
+    err = yaml.Unmarshal(content, layer)
+    if err != nil {
+      return nil, fmt.Errorf("Could not parse %s: %s", path, err.Error())
+    }
+    table.merge(layer)
+  }
+
+  return table, nil
+}
+
+func (t *PricingTable) merge(layer *PricingTable) {
+  if t.Regions == nil {
+    t.Regions = make(map[string]RegionPricing)
+  }
+  for name, region := range layer.Regions {
+    // Copy the maps, since regions can share them through YAML aliases
+    dst := RegionPricing{make(map[string]float64), make(map[string]float64)}
+    for k, v := range t.Regions[name].Instances {
+      dst.Instances[k] = v
+    }
+    for k, v := range t.Regions[name].Volumes {
+      dst.Volumes[k] = v
+    }
+    for k, v := range region.Instances {
+      dst.Instances[k] = v
+    }
+    for k, v := range region.Volumes {
+      dst.Volumes[k] = v
+    }
+    t.Regions[name] = dst
+  }
+}
+
+/**
+ * Returns the hourly price of an instance type in the given region
+ */
+func (t *PricingTable) GetInstancePrice(region string, instanceType string) (float64, error) {
+  pricing, ok := t.Regions[region]
+  if !ok {
+    return 0, fmt.Errorf("No prices for region %s, add them in %s", region, PricingFile)
+  }
+  price, ok := pricing.Instances[instanceType]
+  if !ok {
+    return 0, fmt.Errorf("No price for instance type %s in %s, add it in %s", instanceType, region, PricingFile)
+  }
+  return price, nil
+}
+
+/**
+ * Returns the hourly price of a GB of the given volume type in the given region
+ */
+func (t *PricingTable) GetVolumePrice(region string, volumeType string) (float64, error) {
+  pricing, ok := t.Regions[region]
+  if !ok {
+    return 0, fmt.Errorf("No prices for region %s, add them in %s", region, PricingFile)
+  }
+  price, ok := pricing.Volumes[volumeType]
+  if !ok {
+    return 0, fmt.Errorf("No price for volume type %s in %s, add it in %s", volumeType, region, PricingFile)
+  }
+
+  // Volumes are priced per month, which AWS counts as 730 hours
+  return price / 730, nil
+}
+
+var expirationRe = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)([mhdw])$`)
+
+/**
+ * Parses the expiration of a cluster, as given to cloud-cleaner (ex. `2h`,
+ * `3d` or `1w`)
+ */
+func ParseExpiration(value string) (time.Duration, error) {
+  match := expirationRe.FindStringSubmatch(value)
+  if match == nil {
+    return 0, fmt.Errorf("Could not parse expiration '%s': Expected a number followed by m, h, d or w", value)
+  }
+
+  amount, _ := strconv.ParseFloat(match[1], 64)
+  unit := map[string]time.Duration{
+    "m": time.Minute,
+    "h": time.Hour,
+    "d": 24 * time.Hour,
+    "w": 7 * 24 * time.Hour,
+  }[match[2]]
+
+  return time.Duration(amount * float64(unit)), nil
+}
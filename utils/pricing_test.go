@@ -0,0 +1,99 @@
+package utils
+
+import (
+  "reflect"
+  "testing"
+  "time"
+
+  "gopkg.in/yaml.v3"
+)
+
+func TestParseExpiration(t *testing.T) {
+  tests := []struct {
+    value    string
+    expected time.Duration
+  }{
+    {"30m", 30 * time.Minute},
+    {"2h", 2 * time.Hour},
+    {"1.5h", 90 * time.Minute},
+    {"3d", 72 * time.Hour},
+    {"1w", 7 * 24 * time.Hour},
+  }
+  for _, test := range tests {
+    duration, err := ParseExpiration(test.value)
+    if err != nil {
+      t.Errorf("%s: unexpected error: %s", test.value, err.Error())
+    } else if duration != test.expected {
+      t.Errorf("%s: expected %s, got %s", test.value, test.expected, duration)
+    }
+  }
+
+  for _, value := range []string{"", "2", "h", "2y", "-1h", "2 h", "2hours"} {
+    if _, err := ParseExpiration(value); err == nil {
+      t.Errorf("%s: expected an error", value)
+    }
+  }
+}
+
+func TestPricingTableMerge(t *testing.T) {
+  var table PricingTable
+  err := yaml.Unmarshal([]byte(`
+regions:
+  us-east-1: &us-east-1
+    instances:
+      m5.large: 0.096
+      m5.xlarge: 0.192
+    volumes:
+      gp2: 0.10
+  us-east-2: *us-east-1
+`), &table)
+  if err != nil {
+    t.Fatalf("unexpected error: %s", err.Error())
+  }
+
+  var layer PricingTable
+  err = yaml.Unmarshal([]byte(`
+regions:
+  us-east-2:
+    instances:
+      m5.large: 0.1
+      p3.2xlarge: 3.06
+  eu-west-1:
+    volumes:
+      gp2: 0.11
+`), &layer)
+  if err != nil {
+    t.Fatalf("unexpected error: %s", err.Error())
+  }
+
+  var merged PricingTable
+  merged.merge(&table)
+  merged.merge(&layer)
+
+  expected := map[string]RegionPricing{
+    // The prices of the alias are not changed by the ones of the layer
+    "us-east-1": {
+      Instances: map[string]float64{"m5.large": 0.096, "m5.xlarge": 0.192},
+      Volumes:   map[string]float64{"gp2": 0.10},
+    },
+    "us-east-2": {
+      Instances: map[string]float64{"m5.large": 0.1, "m5.xlarge": 0.192, "p3.2xlarge": 3.06},
+      Volumes:   map[string]float64{"gp2": 0.10},
+    },
+    "eu-west-1": {
+      Instances: map[string]float64{},
+      Volumes:   map[string]float64{"gp2": 0.11},
+    },
+  }
+  if !reflect.DeepEqual(merged.Regions, expected) {
+    t.Errorf("expected %#v, got %#v", expected, merged.Regions)
+  }
+
+  price, err := merged.GetVolumePrice("eu-west-1", "gp2")
+  if err != nil || price != 0.11/730 {
+    t.Errorf("expected the hourly price of gp2, got %f (%v)", price, err)
+  }
+  if _, err := merged.GetInstancePrice("eu-west-1", "m5.large"); err == nil {
+    t.Errorf("expected an error for a missing instance type")
+  }
+}
@@ -6,10 +6,11 @@ import (
   "io/ioutil"
   "os"
   "os/exec"
-  "os/user"
   "path/filepath"
   // "reflect"
+  "sort"
   "strings"
+  "time"
 
   "github.com/gobwas/glob"
   "github.com/hashicorp/hcl"
@@ -21,6 +22,12 @@ var RequiredTerraformVersionPrefix string = "0.11."
 
 type ProjectSandbox struct {
   baseDir string
+  config  *WheelsConfig
+
+  // When in dry-run mode, the files are written in this virtual layer
+  dryRun       bool
+  backup       bool
+  virtualFiles map[string][]byte
 
   // Structure is:
   // { resourceType: { resourceName: { .. merged fields .. } } }
@@ -28,6 +35,46 @@ type ProjectSandbox struct {
 }
 
 func OpenSandbox(baseDir string) (*ProjectSandbox, error) {
+  sandbox, err := openSandboxDir(baseDir)
+  if err != nil {
+    return nil, err
+  }
+
+  sandbox.config, err = LoadWheelsConfig(sandbox.baseDir)
+  if err != nil {
+    return nil, err
+  }
+
+  err = sandbox.ReloadTerraformProject()
+  if err != nil {
+    return nil, err
+  }
+
+  return sandbox, nil
+}
+
+/**
+ * Opens the sandbox even if the configuration or the project files cannot be
+ * parsed, in which case the defaults and an empty project are used. This is
+ * only meant for commands that diagnose such problems.
+ */
+func OpenSandboxLenient(baseDir string) (*ProjectSandbox, error) {
+  sandbox, err := openSandboxDir(baseDir)
+  if err != nil {
+    return nil, err
+  }
+
+  if config, err := LoadWheelsConfig(sandbox.baseDir); err == nil {
+    sandbox.config = config
+  }
+  if tf, err := sandbox.readTerraformProject(true); err == nil {
+    sandbox.tfProject = tf
+  }
+
+  return sandbox, nil
+}
+
+func openSandboxDir(baseDir string) (*ProjectSandbox, error) {
   fPath, err := filepath.Abs(baseDir)
   if err != nil {
     return nil, fmt.Errorf("could not compute absolute path: %s", err.Error())
@@ -45,13 +92,7 @@ func OpenSandbox(baseDir string) (*ProjectSandbox, error) {
     }
   }
 
-  sandbox := &ProjectSandbox{fPath, make(map[string]map[string]map[string]interface{})}
-  err = sandbox.ReloadTerraformProject()
-  if err != nil {
-    return nil, err
-  }
-
-  return sandbox, nil
+  return &ProjectSandbox{fPath, &WheelsConfig{}, false, false, make(map[string][]byte), make(map[string]map[string]map[string]interface{})}, nil
 }
 
 func (s *ProjectSandbox) ReloadTerraformProject() error {
@@ -64,6 +105,39 @@ func (s *ProjectSandbox) ReloadTerraformProject() error {
   return nil
 }
 
+/**
+ * @brief      Return the user and project configuration
+ */
+func (s *ProjectSandbox) GetConfig() *WheelsConfig {
+  return s.config
+}
+
+/**
+ * @brief      Write files to a virtual layer and show their difference to
+ *             the files on disk, instead of writing them.
+ */
+func (s *ProjectSandbox) SetDryRun(dryRun bool) {
+  s.dryRun = dryRun
+}
+
+func (s *ProjectSandbox) IsDryRun() bool {
+  return s.dryRun
+}
+
+/**
+ * @brief      Keep a timestamped copy of every file that is overwritten
+ */
+func (s *ProjectSandbox) SetBackup(backup bool) {
+  s.backup = backup
+}
+
+/**
+ * @brief      Return the absolute path to the project directory
+ */
+func (s *ProjectSandbox) GetBaseDir() string {
+  return s.baseDir
+}
+
 /**
  * @brief      Return the full path to the given file
  */
@@ -89,6 +163,9 @@ func (s *ProjectSandbox) GetTemporaryPath(name string) (string, error) {
  * @brief      Checks if a file exists
  */
 func (s *ProjectSandbox) HasFile(name string) bool {
+  if _, ok := s.virtualFiles[filepath.Clean(name)]; ok {
+    return true
+  }
   _, err := os.Stat(filepath.Join(s.baseDir, name))
   return err == nil
 }
@@ -150,22 +227,34 @@ func (s *ProjectSandbox) HasTerraformFiles() (bool, error) {
  * @return     True if system terraform, False otherwise.
  */
 func (s *ProjectSandbox) HasTerraform() bool {
+  return s.FindTerraform() != nil
+}
+
+/**
+ * @brief      Returns the terraform of the system (if it has the required
+ *             version) or of the sandbox, without downloading it.
+ *
+ * @return     The terraform wrapper, or nil if terraform was not found.
+ */
+func (s *ProjectSandbox) FindTerraform() *TerraformWrapper {
   terraformDir := filepath.Join(s.baseDir, ".terraform")
 
   path, err := exec.LookPath(ExecutableName("terraform"))
   if err == nil {
     w := CreateTeraformWrapper(path)
     if ver, err := w.GetVersion(); err == nil {
-      if strings.HasPrefix(ver, RequiredTerraformVersionPrefix) {
-        return true
+      if strings.HasPrefix(ver, s.config.GetTerraformVersion()) {
+        return w
       }
     }
   }
 
   fBinPath := filepath.Join(terraformDir, "bin")
   fPath := filepath.Join(fBinPath, ExecutableName("terraform"))
-  _, err = os.Stat(fPath)
-  return err == nil
+  if _, err = os.Stat(fPath); err != nil {
+    return nil
+  }
+  return CreateTeraformWrapper(fPath)
 }
 
 /**
@@ -180,13 +269,18 @@ func (s *ProjectSandbox) GetTerraform() (*TerraformWrapper, error) {
   if err == nil {
     w := CreateTeraformWrapper(path)
     if ver, err := w.GetVersion(); err == nil {
-      if strings.HasPrefix(ver, RequiredTerraformVersionPrefix) {
+      if strings.HasPrefix(ver, s.config.GetTerraformVersion()) {
         PrintInfo("Using system terraform v%s", ver)
         return w, nil
       }
     }
   }
 
+  // We can only download the version we know the checksums of
+  if !strings.HasPrefix(upstreamTerraformVersion, s.config.GetTerraformVersion()) {
+    return nil, fmt.Errorf("The required terraform version %s was not found in your PATH and it cannot be downloaded automatically", s.config.GetTerraformVersion())
+  }
+
   fBinPath := filepath.Join(terraformDir, "bin")
   fPath := filepath.Join(fBinPath, ExecutableName("terraform"))
   if err = os.MkdirAll(fBinPath, os.ModePerm); err != nil {
@@ -211,7 +305,7 @@ func (s *ProjectSandbox) GetTerraform() (*TerraformWrapper, error) {
       AndValidateChecksum(checksum).
       EventuallyUnzipTo(fBinPath, 0)
     if err != nil {
-      FatalError(err)
+      return nil, err
     }
   }
 
@@ -234,15 +328,10 @@ func (s *ProjectSandbox) GetTerraform() (*TerraformWrapper, error) {
  *             sandbox directory.
  */
 func (s *ProjectSandbox) InitProject() error {
-  currUserStr := "somebody"
-  if u, err := user.Current(); err == nil {
-    currUserStr = u.Username
-  }
-
   var lines []string = []string{
     `provider "aws" {`,
     `  # Change your default region here`,
-    `  region = "us-west-2"`,
+    fmt.Sprintf(`  region = "%s"`, s.config.GetRegion()),
     `}`,
     ``,
     `# Used to determine your public IP for forwarding rules`,
@@ -252,7 +341,9 @@ func (s *ProjectSandbox) InitProject() error {
     ``,
     `module "dcos" {`,
     `  source  = "dcos-terraform/dcos/aws"`,
-    fmt.Sprintf(`  version = "~> %s"`, GetLatestModuleVersion("0.2.0")),
+    fmt.Sprintf(`  version = "~> %s"`, s.config.GetModuleVersion("aws", func() string {
+      return GetLatestModuleVersion("0.2.0")
+    })),
     ``,
     `  providers = {`,
     `    aws = "aws"`,
@@ -283,8 +374,8 @@ func (s *ProjectSandbox) InitProject() error {
     `  public_agents_instance_type  = "t2.medium"`,
     ``,
     `  tags = {`,
-    `    "expiration" = "1h"`,
-    fmt.Sprintf(`    "owner"      = "%s"`, currUserStr),
+    fmt.Sprintf(`    "expiration" = "%s"`, s.config.GetExpiration()),
+    fmt.Sprintf(`    "owner"      = "%s"`, s.config.GetOwner()),
     `  }`,
     `}`,
     ``,
@@ -301,16 +392,13 @@ func (s *ProjectSandbox) InitProject() error {
     `}`,
   }
 
-  fPrivateKey := filepath.Join(s.baseDir, "cluster-key")
-  fPublicKey := filepath.Join(s.baseDir, "cluster-key.pub")
-  err := CreateRSAKeyPair(fPrivateKey, fPublicKey)
+  err := s.WriteRSAKeyPair("cluster-key", "cluster-key.pub")
   if err != nil {
     return fmt.Errorf("Could not generate RSA keypair: %s", err.Error())
   }
 
   contents := []byte(strings.Join(lines, "\n"))
-  fMain := filepath.Join(s.baseDir, "main.tf")
-  err = ioutil.WriteFile(fMain, contents, 0644)
+  err = s.WriteFile("main.tf", contents)
   if err != nil {
     return fmt.Errorf("Could not create main project file: %s", err.Error())
   }
@@ -323,11 +411,144 @@ func (s *ProjectSandbox) InitProject() error {
  *             sandbox directory.
  */
 func (s *ProjectSandbox) WriteFile(file string, contents []byte) error {
-  err := ioutil.WriteFile(filepath.Join(s.baseDir, file), contents, 0644)
+  return s.WriteFileWithMode(file, contents, 0644)
+}
+
+/**
+ * @brief      Writes a file with the given permissions, creating the
+ *             directories of the file if they are missing.
+ */
+func (s *ProjectSandbox) WriteFileWithMode(file string, contents []byte, mode os.FileMode) error {
+  previous, err := s.ReadFile(file)
+  if err != nil && !os.IsNotExist(err) {
+    return fmt.Errorf("Could not read %s: %s", file, err.Error())
+  }
+  exists := err == nil
+
+  if s.dryRun {
+    s.virtualFiles[filepath.Clean(file)] = contents
+    printDryRunDiff(file, previous, contents, exists)
+    return nil
+  }
+
+  if s.backup && exists && string(previous) != string(contents) {
+    err = s.backupFile(file, previous)
+    if err != nil {
+      return err
+    }
+  }
+
+  fullPath := filepath.Join(s.baseDir, file)
+  if err = os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
+    return fmt.Errorf("Could not create the directory of %s: %s", file, err.Error())
+  }
+
+  err = ioutil.WriteFile(fullPath, contents, mode)
   if err != nil {
     return fmt.Errorf("Could not write %s: %s", file, err.Error())
   }
 
+  PrintEvent("info", "file_written", map[string]interface{}{"file": file}, "")
+  return nil
+}
+
+/**
+ * @brief      Writes a file outside of the project directory, such as the
+ *             user configuration, showing the changes instead in dry-run
+ *             and keeping a copy of the previous file in backup mode.
+ */
+func (s *ProjectSandbox) WriteExternalFile(path string, contents []byte) error {
+  previous, err := ioutil.ReadFile(path)
+  if err != nil && !os.IsNotExist(err) {
+    return fmt.Errorf("Could not read %s: %s", path, err.Error())
+  }
+
+  exists := err == nil
+
+  if s.dryRun {
+    printDryRunDiff(path, previous, contents, exists)
+    return nil
+  }
+
+  if s.backup && exists && string(previous) != string(contents) {
+    err = s.backupFile(path, previous)
+    if err != nil {
+      return err
+    }
+  }
+
+  err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+  if err != nil {
+    return fmt.Errorf("Could not create %s: %s", filepath.Dir(path), err.Error())
+  }
+  err = ioutil.WriteFile(path, contents, 0644)
+  if err != nil {
+    return fmt.Errorf("Could not write %s: %s", path, err.Error())
+  }
+
+  PrintEvent("info", "file_written", map[string]interface{}{"file": path}, "")
+  return nil
+}
+
+/**
+ * @brief      Generates an RSA key pair and writes it in the given project
+ *             files, through the dry-run and backup modes of the sandbox.
+ */
+func (s *ProjectSandbox) WriteRSAKeyPair(privateFile string, publicFile string) error {
+  privateKey, publicKey, err := GenerateRSAKeyPair()
+  if err != nil {
+    return err
+  }
+
+  err = s.WriteFileWithMode(privateFile, privateKey, 0600)
+  if err != nil {
+    return err
+  }
+  return s.WriteFileWithMode(publicFile, publicKey, 0600)
+}
+
+/**
+ * @brief      Shows the changes that writing a file would do in dry-run.
+ */
+func printDryRunDiff(file string, previous []byte, contents []byte, exists bool) {
+  aName := "a/" + file
+  if !exists {
+    aName = "/dev/null"
+  }
+  diff := UnifiedDiff(aName, "b/"+file, previous, contents, 3)
+  if diff == "" {
+    PrintEvent("info", "file_unchanged", map[string]interface{}{"file": file}, "Dry-run: %s would not change", file)
+  } else if IsJSONOutput() {
+    PrintEvent("info", "file_diff", map[string]interface{}{"file": file, "diff": diff}, "Dry-run: not writing %s", file)
+  } else {
+    PrintInfo("Dry-run: not writing %s, the changes would be:", file)
+    PrintDiff(diff)
+  }
+}
+
+/**
+ * @brief      Keeps a timestamped copy of the contents of a file that is
+ *             about to be overwritten.
+ */
+func (s *ProjectSandbox) backupFile(file string, contents []byte) error {
+  // With nanoseconds, so that writing the same file twice in one second
+  // keeps both copies
+  backupName := fmt.Sprintf("%s.%s", filepath.Clean(file), time.Now().Format("20060102-150405.000000000"))
+  backupPath := filepath.Join(s.baseDir, ".wheels", "backups", backupName)
+
+  err := os.MkdirAll(filepath.Dir(backupPath), os.ModePerm)
+  if err != nil {
+    return fmt.Errorf("Could not create backup directory: %s", err.Error())
+  }
+
+  err = ioutil.WriteFile(backupPath, contents, 0644)
+  if err != nil {
+    return fmt.Errorf("Could not back-up %s: %s", file, err.Error())
+  }
+
+  relPath, _ := filepath.Rel(s.baseDir, backupPath)
+  PrintEvent("info", "file_backup", map[string]interface{}{"file": file, "backup": relPath},
+    "Keeping a copy of the previous %s in %s", file, relPath)
   return nil
 }
 
@@ -341,6 +562,9 @@ func (s *ProjectSandbox) WriteFormattedTerraformFile(file string, contents []byt
 }
 
 func (s *ProjectSandbox) ReadFile(file string) ([]byte, error) {
+  if contents, ok := s.virtualFiles[filepath.Clean(file)]; ok {
+    return contents, nil
+  }
   return ioutil.ReadFile(filepath.Join(s.baseDir, file))
 }
 
@@ -359,20 +583,60 @@ func (s *ProjectSandbox) ReadTerraformFile(file string) (map[string]interface{},
   return dst, nil
 }
 
-func (s *ProjectSandbox) ReadTerraformProject() (map[string]map[string]map[string]interface{}, error) {
+/**
+ * Returns the names of the terraform files in the project directory, sorted
+ */
+func (s *ProjectSandbox) ListTerraformFiles() ([]string, error) {
   files, err := ioutil.ReadDir(s.baseDir)
   if err != nil {
     return nil, fmt.Errorf("Could not enumerate files: %s", err.Error())
   }
 
-  dst := make(map[string]map[string]map[string]interface{})
+  // Include the files that only exist in the virtual layer
+  var fileNames []string
   for _, file := range files {
-    if !strings.HasSuffix(file.Name(), ".tf") {
-      continue
+    if _, ok := s.virtualFiles[file.Name()]; !ok {
+      fileNames = append(fileNames, file.Name())
+    }
+  }
+  for name := range s.virtualFiles {
+    if filepath.Dir(name) == "." {
+      fileNames = append(fileNames, name)
+    }
+  }
+  sort.Strings(fileNames)
+
+  var tfFiles []string
+  for _, fileName := range fileNames {
+    if strings.HasSuffix(fileName, ".tf") {
+      tfFiles = append(tfFiles, fileName)
     }
+  }
+
+  return tfFiles, nil
+}
+
+func (s *ProjectSandbox) ReadTerraformProject() (map[string]map[string]map[string]interface{}, error) {
+  return s.readTerraformProject(false)
+}
 
-    slice, err := s.ReadTerraformFile(file.Name())
+/**
+ * Reads and merges all the terraform files of the project, optionally
+ * skipping the ones that cannot be parsed
+ */
+func (s *ProjectSandbox) readTerraformProject(skipInvalid bool) (map[string]map[string]map[string]interface{}, error) {
+  fileNames, err := s.ListTerraformFiles()
+  if err != nil {
+    return nil, err
+  }
+
+  dst := make(map[string]map[string]map[string]interface{})
+  for _, fileName := range fileNames {
+    slice, err := s.ReadTerraformFile(fileName)
     if err != nil {
+      if skipInvalid {
+        continue
+      }
       return nil, err
     }
 
@@ -391,6 +655,7 @@ func (s *ProjectSandbox) ReadTerraformProject() (map[string]map[string]map[strin
             }
 
             dstResName["_name"] = resName
+            dstResName["_file"] = fileName
 
             if resValueMapArray, ok := _resNameArr.([]map[string]interface{}); ok {
               for _, resValueMap := range resValueMapArray {
@@ -432,6 +697,10 @@ func (s *ProjectSandbox) EnsureDcosProviderTF() error {
   return nil
 }
 
+func (s *ProjectSandbox) GetTerraformProject() map[string]map[string]map[string]interface{} {
+  return s.tfProject
+}
+
 func (s *ProjectSandbox) GetTerraformResources(resType string) map[string]map[string]interface{} {
   if found, ok := s.tfProject[resType]; ok {
     return found
@@ -474,52 +743,51 @@ func (s *ProjectSandbox) GetTerraformResourcesMatchingName(resType string, nameG
   return ret
 }
 
-func (s *ProjectSandbox) PrintVariableDefs() {
-  var listNames []string
-  var mapNames []string
-
-  fmt.Printf("var tfc TerraformFileConfig\n")
-  fmt.Printf("tfc.Flags = flag.NewFlagSet(p.GetName(), flag.ContinueOnError)\n")
+/**
+ * Returns the aliases of the blocks of the given provider. The aliases are
+ * read from the files, since the blocks are merged in the project.
+ */
+func (s *ProjectSandbox) GetProviderAliases(name string) []string {
+  var aliases []string
+  fileNames, err := s.ListTerraformFiles()
+  if err != nil {
+    return nil
+  }
 
-  for k, v := range s.GetTerraformResources("variable") {
-    desc := ""
-    if v, ok := v["description"]; ok {
-      desc = v.(string)
+  for _, fileName := range fileNames {
+    slice, err := s.ReadTerraformFile(fileName)
+    if err != nil {
+      continue
     }
 
-    // defval := ""
-    // if v, ok := v["default"]; ok {
-    //  defval = fmt.Sprintf("%#v", v)
-    // }
-
-    ftype := "string"
-    if v, ok := v["type"]; ok {
-      ftype = v.(string)
+    providers, _ := slice["provider"].([]map[string]interface{})
+    for _, provider := range providers {
+      blocks, _ := provider[name].([]map[string]interface{})
+      for _, block := range blocks {
+        if alias, ok := block["alias"].(string); ok {
+          aliases = append(aliases, alias)
+        }
+      }
     }
+  }
 
-    if ftype == "string" {
-      jv, _ := json.Marshal(desc)
-      fmt.Printf("tfc.Flags.String(\"%s\", \"\", %s)\n", k, string(jv))
-    }
-    if ftype == "list" {
-      desc += " (use multiple times to add multiple values)"
-      jv, _ := json.Marshal(desc)
-      fmt.Printf("tfc.Flags.String(\"%s\", \"\", %s)\n", k, string(jv))
-      listNames = append(listNames, k)
-    }
-    if ftype == "map" {
-      desc += " (use key=value format)"
-      jv, _ := json.Marshal(desc)
-      fmt.Printf("tfc.Flags.String(\"%s\", \"\", %s)\n", k, string(jv))
-      mapNames = append(listNames, k)
-    }
+  sort.Strings(aliases)
+  return aliases
+}
+
+/**
+ * Prints the variables of the module in the sandbox in the format of the
+ * module variable snapshots
+ */
+func (s *ProjectSandbox) PrintVariableDefs() {
+  vars, err := parseModuleVariables(s.baseDir)
+  if err != nil {
+    FatalError(err)
   }
 
-  fmt.Printf("\n")
-  fmt.Printf("tfc.ListFlags = %#v\n", listNames)
-  fmt.Printf("tfc.MapFlags = %#v\n", mapNames)
-  fmt.Printf("\n")
-  fmt.Printf("tfc.PreLines = []string{}\n")
-  fmt.Printf("tfc.BodyLines = []string{}\n")
-  fmt.Printf("tfc.PostLines = []string{}\n")
+  for _, v := range vars {
+    name, _ := json.Marshal(v.Name)
+    desc, _ := json.Marshal(v.Description)
+    fmt.Printf("{Name: %s, Type: \"%s\", Description: %s},\n", string(name), v.Type, string(desc))
+  }
 }
@@ -76,5 +76,16 @@ func (w *SSHAgentWrapper) Stop() error {
   }
 
   PrintInfo("Stopping ssh-agent")
-  return proc.Kill()
+  err = proc.Kill()
+  if err != nil {
+    return fmt.Errorf("Could not stop ssh-agent: %s", err.Error())
+  }
+
+  // The agent cannot clean-up its socket when killed
+  err = os.Remove(w.Socket)
+  if err != nil && !os.IsNotExist(err) {
+    return fmt.Errorf("Could not remove ssh-agent socket: %s", err.Error())
+  }
+
+  return nil
 }
@@ -1,17 +1,39 @@
 package utils
 
 import (
+  "encoding/json"
   "fmt"
   "regexp"
+  "strings"
 )
 
 type TerraformWrapper struct {
   terraformPath string
   env           []string
+  exitCode      int
+  command       string
+  args          []string
 }
 
 func CreateTeraformWrapper(fName string) *TerraformWrapper {
-  return &TerraformWrapper{fName, nil}
+  return &TerraformWrapper{fName, nil, 0, "", nil}
+}
+
+/**
+ * Sets the terraform command (ex. `apply`) that the plugins are started for,
+ * together with all its arguments
+ */
+func (w *TerraformWrapper) SetCommand(command string, args []string) {
+  w.command = command
+  w.args = args
+}
+
+func (w *TerraformWrapper) GetCommand() string {
+  return w.command
+}
+
+func (w *TerraformWrapper) GetArgs() []string {
+  return w.args
 }
 
 func (w *TerraformWrapper) SetEnv(key string, value string) {
@@ -34,6 +56,55 @@ func (w *TerraformWrapper) GetVersion() (string, error) {
 }
 
 func (w *TerraformWrapper) Invoke(args []string) error {
-  _, err := ExecuteAndPassthrough(w.env, w.terraformPath, args...)
-  return err
+  code, err := ExecuteAndPassthrough(w.env, w.terraformPath, args...)
+  if err != nil {
+    return err
+  }
+
+  w.exitCode = code
+  PrintEvent("info", "terraform_exit", map[string]interface{}{
+    "args":      args,
+    "exit_code": code,
+  }, "")
+  return nil
+}
+
+/**
+ * Returns the exit code of the last terraform invocation
+ */
+func (w *TerraformWrapper) GetExitCode() int {
+  return w.exitCode
+}
+
+/**
+ * Returns the outputs of the terraform state, with the non-string values
+ * encoded as JSON
+ */
+func (w *TerraformWrapper) GetOutputs() (map[string]string, error) {
+  code, sout, serr, err := ExecuteAndCollect(w.env, w.terraformPath, "output", "-json")
+  if err != nil {
+    return nil, err
+  }
+  if code != 0 {
+    return nil, fmt.Errorf("Could not read terraform outputs: %s", strings.TrimSpace(serr))
+  }
+
+  var outputs map[string]struct {
+    Value interface{} `json:"value"`
+  }
+  err = json.Unmarshal([]byte(sout), &outputs)
+  if err != nil {
+    return nil, fmt.Errorf("Could not parse terraform outputs: %s", err.Error())
+  }
+
+  ret := make(map[string]string)
+  for name, output := range outputs {
+    if str, ok := output.Value.(string); ok {
+      ret[name] = str
+    } else {
+      bt, _ := json.Marshal(output.Value)
+      ret[name] = string(bt)
+    }
+  }
+  return ret, nil
 }
@@ -1,12 +1,13 @@
 package utils
 
 import (
-  "encoding/json"
   "flag"
   "fmt"
   "io"
   "os"
   "strings"
+
+  "github.com/hashicorp/hcl/hcl/ast"
 )
 
 type TerraformFileConfig struct {
@@ -19,11 +20,43 @@ type TerraformFileConfig struct {
   BodyLines []string
   PostLines []string
 
+  // The keys of the block the flags are written to (ex. `module`, `dcos`)
+  Block []string
+
+  // The types of the parameters, the rest are strings
+  Params map[string]ParamSpec
+
   BodyPrefix string
 
   printOutput io.Writer
 }
 
+/**
+ * A flag that can be given multiple times, collecting all the values
+ */
+type MultiValueFlag struct {
+  Values []string
+}
+
+func (v *MultiValueFlag) String() string {
+  return strings.Join(v.Values, ",")
+}
+
+func (v *MultiValueFlag) Set(value string) error {
+  v.Values = append(v.Values, value)
+  return nil
+}
+
+/**
+ * Returns all the values given to a flag
+ */
+func flagValues(f *flag.Flag) []string {
+  if mv, ok := f.Value.(*MultiValueFlag); ok {
+    return mv.Values
+  }
+  return []string{f.Value.String()}
+}
+
 func wrapLongLines(text string, lineWidth int) []string {
   var ret []string
   words := strings.Fields(strings.TrimSpace(text))
@@ -78,6 +111,48 @@ func (c *TerraformFileConfig) printFlag(f *flag.Flag) {
   c.printOutput.Write([]byte(strings.Join(retLines, "\n")))
 }
 
+/**
+ * Defines a flag for every module variable, classifying the list and map
+ * variables. Flags that are already defined are left as they are.
+ */
+func (c *TerraformFileConfig) AddModuleVariables(vars []ModuleVariable) {
+  for _, v := range vars {
+    if c.Flags.Lookup(v.Name) != nil {
+      continue
+    }
+    if _, ok := c.Params[v.Name]; !ok {
+      if spec, ok := inferParamSpec(v); ok {
+        if c.Params == nil {
+          c.Params = make(map[string]ParamSpec)
+        }
+        c.Params[v.Name] = spec
+      }
+    }
+
+    usage := v.Description
+    switch v.Type {
+    case "list":
+      usage += " (use multiple times to add multiple values)"
+    case "map":
+      usage += " (use key=value format, multiple times to add multiple values)"
+    }
+    if def := v.FormatDefault(); def != "" {
+      usage += fmt.Sprintf(" [default: %s]", def)
+    }
+
+    switch v.Type {
+    case "list":
+      c.Flags.Var(&MultiValueFlag{}, v.Name, usage)
+      c.ListFlags = append(c.ListFlags, v.Name)
+    case "map":
+      c.Flags.Var(&MultiValueFlag{}, v.Name, usage)
+      c.MapFlags = append(c.MapFlags, v.Name)
+    default:
+      c.Flags.String(v.Name, "", usage)
+    }
+  }
+}
+
 func (c *TerraformFileConfig) IsList(name string) bool {
   for _, n := range c.ListFlags {
     if n == name {
@@ -113,88 +188,78 @@ func (c *TerraformFileConfig) SetOutput(output io.Writer) {
   c.printOutput = output
 }
 
+/**
+ * Composes the file from the pre, body and post lines, and then sets the
+ * attributes of the flags given in the command-line in `Block`, replacing the
+ * default values of the body.
+ */
 func (c *TerraformFileConfig) Generate() ([]byte, error) {
-  var lines []string = c.BodyLines
-  var errs []error
+  var allLines []string
+  allLines = append(allLines, c.PreLines...)
+  allLines = append(allLines, c.BodyLines...)
+  allLines = append(allLines, c.PostLines...)
+  content := []byte(strings.Join(allLines, "\n"))
 
-  listValues := make(map[string][]string)
-  mapValues := make(map[string]map[string]string)
+  if len(c.Block) == 0 {
+    return nil, fmt.Errorf("Missing the block to write the parameters to")
+  }
+
+  err := c.Validate()
+  if err != nil {
+    return nil, err
+  }
 
+  var errs []string
   c.Flags.Visit(func(f *flag.Flag) {
     if c.IsIgnored(f.Name) {
       return
     }
 
-    // If that variable already exists in the body, remove it
-    for i, l := range lines {
-      if strings.Contains(l, f.Name) {
-        copy(lines[i:], lines[i+1:]) // Shift a[i+1:] left one index.
-        lines[len(lines)-1] = ""     // Erase last element (write zero value).
-        lines = lines[:len(lines)-1] // Truncate slice.
-        break
-      }
-    }
-
+    var updated []byte
+    var err error
+    spec := c.getParamSpec(f.Name)
     if c.IsList(f.Name) {
-      // If that's a list, add it on the list
-      var vals []string
-      if v, ok := listValues[f.Name]; ok {
-        vals = v
+      var items []ast.Node
+      for _, value := range flagValues(f) {
+        items = append(items, spec.Node(value))
       }
-
-      vals = append(vals, fmt.Sprintf("%s", f.Value.String()))
-      listValues[f.Name] = vals
+      updated, err = SetHCLAttribute(content, c.Block, f.Name, HCLList(items))
 
     } else if c.IsMap(f.Name) {
-      // If that's a map, add it on the maps
-      value := f.Value.String()
-      kv := strings.Split(value, "=")
-      if len(kv) < 2 {
-        errs = append(errs, fmt.Errorf("Could not parse '%s': Expected key=value format", value))
-        return
-      }
+      updated = content
+      for _, value := range flagValues(f) {
+        kv := strings.SplitN(value, "=", 2)
+        if len(kv) < 2 {
+          errs = append(errs, fmt.Sprintf("Could not parse %s '%s': Expected key=value format", f.Name, value))
+          return
+        }
 
-      vals := make(map[string]string)
-      if v, ok := mapValues[f.Name]; ok {
-        vals = v
+        // Add the value to the map if it's already defined
+        var existing ast.Node
+        existing, err = GetHCLAttribute(updated, c.Block, f.Name)
+        if _, ok := existing.(*ast.ObjectType); ok {
+          updated, err = SetHCLAttribute(updated, append(append([]string{}, c.Block...), f.Name), kv[0], spec.Node(kv[1]))
+        } else if err == nil {
+          updated, err = SetHCLAttribute(updated, c.Block, f.Name, HCLMap(map[string]ast.Node{kv[0]: spec.Node(kv[1])}))
+        }
+        if err != nil {
+          break
+        }
       }
 
-      vals[kv[0]] = kv[1]
-      mapValues[f.Name] = vals
-
     } else {
-      // Otherwise append it to the list
-      v, _ := json.Marshal(f.Value.String())
-      lines = append(lines, fmt.Sprintf("%s = %s", f.Name, string(v)))
+      updated, err = SetHCLAttribute(content, c.Block, f.Name, spec.Node(f.Value.String()))
     }
-  })
 
-  // Then expand the lists
-  for varName, list := range listValues {
-    lines = append(lines, "")
-    lines = append(lines, fmt.Sprintf("%s = [", varName))
-    for _, item := range list {
-      v, _ := json.Marshal(item)
-      lines = append(lines, fmt.Sprintf("  %s,", v))
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not set %s: %s", f.Name, err.Error()))
+      return
     }
-    lines = append(lines, fmt.Sprintf("]"))
-  }
+    content = updated
+  })
 
-  // Then expand the maps
-  for varName, list := range mapValues {
-    lines = append(lines, "")
-    lines = append(lines, fmt.Sprintf("%s = {", varName))
-    for key, item := range list {
-      v, _ := json.Marshal(item)
-      lines = append(lines, fmt.Sprintf("  %s = %s", key, v))
-    }
-    lines = append(lines, fmt.Sprintf("}"))
+  if len(errs) > 0 {
+    return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
   }
-
-  // Compose all lines
-  allLines := append(c.PreLines, lines...)
-  allLines = append(allLines, c.PostLines...)
-
-  content := []byte(strings.Join(allLines, "\n"))
   return content, nil
 }
@@ -0,0 +1,273 @@
+package utils
+
+import (
+  "flag"
+  "fmt"
+  "net"
+  "sort"
+  "strconv"
+  "strings"
+
+  "github.com/hashicorp/hcl/hcl/ast"
+  "github.com/hashicorp/hcl/hcl/token"
+)
+
+type ParamType string
+
+const (
+  ParamString ParamType = "string"
+  ParamInt    ParamType = "int"
+  ParamBool   ParamType = "bool"
+  ParamCIDR   ParamType = "cidr"
+  ParamEnum   ParamType = "enum"
+)
+
+/**
+ * The type of a parameter, used to validate the values given to the flags
+ * and to write them with the correct type. For list and map flags, the type
+ * applies to every item.
+ */
+type ParamSpec struct {
+  Type ParamType
+
+  // The allowed values of enums
+  Choices []string
+
+  // The minimum value of integers
+  Min int
+
+  // If integers must be odd (ex. the number of masters)
+  Odd bool
+}
+
+func IntParam(min int) ParamSpec {
+  return ParamSpec{Type: ParamInt, Min: min}
+}
+
+func OddIntParam(min int) ParamSpec {
+  return ParamSpec{Type: ParamInt, Min: min, Odd: true}
+}
+
+func BoolParam() ParamSpec {
+  return ParamSpec{Type: ParamBool}
+}
+
+func CIDRParam() ParamSpec {
+  return ParamSpec{Type: ParamCIDR}
+}
+
+func EnumParam(choices ...string) ParamSpec {
+  return ParamSpec{Type: ParamEnum, Choices: choices}
+}
+
+/**
+ * Values with interpolations are only known to terraform, so they are
+ * never validated
+ */
+func isInterpolation(value string) bool {
+  return strings.Contains(value, "${")
+}
+
+/**
+ * Checks if the given value is valid for this parameter
+ */
+func (p ParamSpec) Validate(value string) error {
+  if isInterpolation(value) {
+    return nil
+  }
+
+  switch p.Type {
+  case ParamInt:
+    n, err := strconv.Atoi(value)
+    if err != nil {
+      return fmt.Errorf("'%s' is not a number", value)
+    }
+    if n < p.Min {
+      return fmt.Errorf("%d is less than %d", n, p.Min)
+    }
+    if p.Odd && n%2 == 0 {
+      return fmt.Errorf("%d is not an odd number", n)
+    }
+
+  case ParamBool:
+    if _, err := strconv.ParseBool(value); err != nil {
+      return fmt.Errorf("'%s' is not true or false", value)
+    }
+
+  case ParamCIDR:
+    if _, _, err := net.ParseCIDR(value); err != nil {
+      return fmt.Errorf("'%s' is not a valid CIDR (ex. 10.0.0.0/16)", value)
+    }
+
+  case ParamEnum:
+    for _, c := range p.Choices {
+      if c == value {
+        return nil
+      }
+    }
+    return fmt.Errorf("'%s' is not one of: %s", value, strings.Join(p.Choices, ", "))
+  }
+
+  return nil
+}
+
+/**
+ * Checks the value of a parameter given as HCL. The items of lists and the
+ * values of maps are checked one by one, and all the problems are returned.
+ */
+func (p ParamSpec) ValidateHCLValue(node ast.Node) []string {
+  var problems []string
+  switch v := node.(type) {
+  case *ast.LiteralType:
+    value := v.Token.Text
+    if v.Token.Type == token.STRING {
+      if unquoted, err := strconv.Unquote(value); err == nil {
+        value = unquoted
+      }
+    }
+    if err := p.Validate(value); err != nil {
+      problems = append(problems, err.Error())
+    }
+  case *ast.ListType:
+    for _, item := range v.List {
+      problems = append(problems, p.ValidateHCLValue(item)...)
+    }
+  case *ast.ObjectType:
+    for _, item := range v.List.Items {
+      problems = append(problems, p.ValidateHCLValue(item.Val)...)
+    }
+  }
+  return problems
+}
+
+/**
+ * Returns the value node of a valid value
+ */
+func (p ParamSpec) Node(value string) ast.Node {
+  if isInterpolation(value) {
+    return HCLString(value)
+  }
+
+  switch p.Type {
+  case ParamInt:
+    return HCLNumber(value)
+  case ParamBool:
+    b, _ := strconv.ParseBool(value)
+    return HCLBool(b)
+  }
+  return HCLString(value)
+}
+
+/**
+ * Parses a value given as HCL in the command-line, like ParseHCLValue, but
+ * numbers and booleans are only written unquoted for the int and bool
+ * parameters. Terraform would change them otherwise (ex. `1.10` to "1.1").
+ */
+func (p ParamSpec) ParseHCLValue(text string) ast.Node {
+  return p.typeHCLValue(ParseHCLValue(text))
+}
+
+func (p ParamSpec) typeHCLValue(node ast.Node) ast.Node {
+  switch v := node.(type) {
+  case *ast.LiteralType:
+    switch v.Token.Type {
+    case token.NUMBER, token.FLOAT:
+      if p.Type != ParamInt {
+        return HCLString(v.Token.Text)
+      }
+    case token.BOOL:
+      if p.Type != ParamBool {
+        return HCLString(v.Token.Text)
+      }
+    }
+  case *ast.ListType:
+    for i, item := range v.List {
+      v.List[i] = p.typeHCLValue(item)
+    }
+  case *ast.ObjectType:
+    for _, item := range v.List.Items {
+      item.Val = p.typeHCLValue(item.Val)
+    }
+  }
+  return node
+}
+
+/**
+ * Guesses the type of a module variable from its default value
+ */
+func inferParamSpec(v ModuleVariable) (ParamSpec, bool) {
+  switch tv := v.Default.(type) {
+  case bool:
+    return BoolParam(), true
+  case int, int64, float64:
+    return IntParam(0), true
+  case string:
+    if tv == "true" || tv == "false" {
+      return BoolParam(), true
+    }
+  }
+  return ParamSpec{}, false
+}
+
+func (c *TerraformFileConfig) getParamSpec(name string) ParamSpec {
+  if spec, ok := c.Params[name]; ok {
+    return spec
+  }
+  return ParamSpec{Type: ParamString}
+}
+
+/**
+ * Validates all the flags given in the command-line and returns all the
+ * problems found at once
+ */
+func (c *TerraformFileConfig) Validate() error {
+  problems := make(map[string][]string)
+
+  c.Flags.Visit(func(f *flag.Flag) {
+    if c.IsIgnored(f.Name) {
+      return
+    }
+
+    spec := c.getParamSpec(f.Name)
+    for _, value := range flagValues(f) {
+      if c.IsMap(f.Name) {
+        kv := strings.SplitN(value, "=", 2)
+        if len(kv) < 2 {
+          problems[f.Name] = append(problems[f.Name], fmt.Sprintf("'%s' is not in key=value format", value))
+          continue
+        }
+        value = kv[1]
+      }
+
+      if err := spec.Validate(value); err != nil {
+        problems[f.Name] = append(problems[f.Name], err.Error())
+      }
+    }
+  })
+
+  return InvalidParamsError(problems)
+}
+
+/**
+ * Returns the error that lists the problems found in the parameters, sorted
+ * by name, or nil if there are none
+ */
+func InvalidParamsError(problems map[string][]string) error {
+  if len(problems) == 0 {
+    return nil
+  }
+
+  var names []string
+  for name := range problems {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  lines := []string{"Invalid parameters:"}
+  for _, name := range names {
+    for _, problem := range problems[name] {
+      lines = append(lines, fmt.Sprintf("  -%s: %s", name, problem))
+    }
+  }
+  return fmt.Errorf("%s", strings.Join(lines, "\n"))
+}
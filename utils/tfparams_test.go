@@ -0,0 +1,46 @@
+package utils
+
+import (
+  "testing"
+
+  "github.com/hashicorp/hcl/hcl/ast"
+  "github.com/hashicorp/hcl/hcl/printer"
+)
+
+func formatHCLNode(t *testing.T, node ast.Node) string {
+  content, err := SetHCLAttribute([]byte("module \"dcos\" {\n}\n"), []string{"module", "dcos"}, "value", node)
+  if err != nil {
+    t.Fatalf("Could not set the value: %s", err.Error())
+  }
+  content, err = printer.Format(content)
+  if err != nil {
+    t.Fatalf("Could not format the value: %s", err.Error())
+  }
+  return string(content)
+}
+
+func TestParamSpecParseHCLValue(t *testing.T) {
+  tests := []struct {
+    spec     ParamSpec
+    value    string
+    expected string
+  }{
+    {ParamSpec{}, "1.10", `"1.10"`},
+    {ParamSpec{}, "2.0", `"2.0"`},
+    {ParamSpec{}, "true", `"true"`},
+    {ParamSpec{}, `"quoted"`, `"quoted"`},
+    {ParamSpec{}, `["1.10", 2]`, `["1.10", "2"]`},
+    {IntParam(1), "3", `3`},
+    {BoolParam(), "false", `false`},
+    {CIDRParam(), `{a = 1}`, `{
+    "a" = "1"
+  }`},
+  }
+
+  for _, test := range tests {
+    expected := "module \"dcos\" {\n  value = " + test.expected + "\n}\n"
+    if got := formatHCLNode(t, test.spec.ParseHCLValue(test.value)); got != expected {
+      t.Errorf("%s (%s): expected:\n%s\ngot:\n%s", test.value, test.spec.Type, expected, got)
+    }
+  }
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+  "fmt"
+  "sort"
+  "strings"
+
+  "github.com/hashicorp/hcl/hcl/ast"
+  "github.com/hashicorp/hcl/hcl/parser"
+)
+
+// The file where the variables of the generated clusters are defined
+var VariablesFile string = "variables.tf"
+
+// The file with the values that terraform loads automatically
+var DefaultTfvarsFile string = "terraform.tfvars"
+
+type ExtractedVariable struct {
+  Name        string
+  Type        string // `string`, `list` or `map`
+  Description string
+  Value       string // The HCL text of the value
+}
+
+// The attributes of a module block that cannot be variables
+var nonVariableModuleAttributes []string = []string{"source", "version", "providers"}
+
+/**
+ * Replaces the values of the attributes of the given block with references to
+ * variables named `<prefix><attribute>`, and returns the variables with their
+ * values. The values that use interpolations are kept in the block, since
+ * they cannot be given in a tfvars file, and so are the attributes in `keep`.
+ */
+func ExtractHCLVariables(content []byte, block []string, prefix string, vars []ModuleVariable, keep []string) ([]byte, []ExtractedVariable, error) {
+  obj, err := parseHCLObject(content, block)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  moduleVars := make(map[string]ModuleVariable)
+  for _, v := range vars {
+    moduleVars[v.Name] = v
+  }
+
+  skipNames := append(append([]string{}, nonVariableModuleAttributes...), keep...)
+
+  var extracted []ExtractedVariable
+  var items []*ast.ObjectItem
+  for _, item := range obj.List.Items {
+    if len(item.Keys) != 1 {
+      continue
+    }
+    name := hclKeyText(item.Keys[0])
+    skip := false
+    for _, n := range skipNames {
+      if n == name {
+        skip = true
+      }
+    }
+    value := string(content[item.Val.Pos().Offset:hclNodeEnd(item.Val)])
+    if skip || strings.Contains(value, "${") {
+      continue
+    }
+
+    v := ExtractedVariable{prefix + name, hclValueType(item.Val), "", value}
+    if mv, ok := moduleVars[name]; ok {
+      v.Type = mv.Type
+      v.Description = mv.Description
+    }
+    extracted = append(extracted, v)
+    items = append(items, item)
+  }
+
+  // Replace the values from the end, so the offsets stay valid
+  for i := len(items) - 1; i >= 0; i-- {
+    ref := fmt.Sprintf(`"${var.%s}"`, extracted[i].Name)
+    content = spliceBytes(content, items[i].Val.Pos().Offset, hclNodeEnd(items[i].Val), ref)
+  }
+
+  return content, extracted, nil
+}
+
+func hclValueType(node ast.Node) string {
+  switch node.(type) {
+  case *ast.ListType:
+    return "list"
+  case *ast.ObjectType:
+    return "map"
+  }
+  return "string"
+}
+
+/**
+ * Adds the definitions of the given variables to `variables.tf` (keeping the
+ * ones that already exist) and sets their values in the given tfvars file
+ */
+func (s *ProjectSandbox) WriteVariables(vars []ExtractedVariable, tfvarsFile string) error {
+  if tfvarsFile == "" {
+    tfvarsFile = DefaultTfvarsFile
+  }
+
+  defs, err := s.readOptionalFile(VariablesFile)
+  if err != nil {
+    return err
+  }
+  values, err := s.readOptionalFile(tfvarsFile)
+  if err != nil {
+    return err
+  }
+
+  sorted := append([]ExtractedVariable{}, vars...)
+  sort.Slice(sorted, func(i, j int) bool {
+    return sorted[i].Name < sorted[j].Name
+  })
+
+  var errs []string
+  for _, v := range sorted {
+    if _, err := parseHCLObject(defs, []string{"variable", v.Name}); err != nil {
+      lines := []string{
+        fmt.Sprintf(`variable "%s" {`, v.Name),
+        fmt.Sprintf(`  type        = "%s"`, v.Type),
+        fmt.Sprintf(`  description = %s`, FormatJSON(v.Description)),
+        `}`,
+      }
+      defs = append(defs, []byte("\n"+strings.Join(lines, "\n")+"\n")...)
+    }
+
+    file, err := parser.Parse([]byte("value = " + v.Value))
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not parse the value of %s: %s", v.Name, err.Error()))
+      continue
+    }
+    value := rebuildHCLValue(file.Node.(*ast.ObjectList).Items[0].Val)
+    updated, err := SetHCLAttribute(values, nil, v.Name, value)
+    if err != nil {
+      errs = append(errs, fmt.Sprintf("Could not set %s in %s: %s", v.Name, tfvarsFile, err.Error()))
+      continue
+    }
+    values = updated
+  }
+  if len(errs) > 0 {
+    return fmt.Errorf("%s", strings.Join(errs, "; "))
+  }
+
+  err = s.WriteFormattedTerraformFile(VariablesFile, defs)
+  if err != nil {
+    return err
+  }
+  return s.WriteFormattedTerraformFile(tfvarsFile, values)
+}
+
+/**
+ * Returns the contents of a file, or nothing if it does not exist
+ */
+func (s *ProjectSandbox) readOptionalFile(file string) ([]byte, error) {
+  if !s.HasFile(file) {
+    return []byte{}, nil
+  }
+  content, err := s.ReadFile(file)
+  if err != nil {
+    return nil, fmt.Errorf("Could not read %s: %s", file, err.Error())
+  }
+  return content, nil
+}
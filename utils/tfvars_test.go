@@ -0,0 +1,67 @@
+package utils
+
+import (
+  "reflect"
+  "testing"
+)
+
+func TestExtractHCLVariables(t *testing.T) {
+  content := `module "dcos-prod" {
+  source  = "dcos-terraform/dcos/aws"
+  version = "~> 0.2.0"
+
+  cluster_name        = "prod"
+  ssh_public_key_file = "cluster-prod-key.pub"
+  admin_ips           = ["${data.http.whatismyip-prod.body}/32"]
+  num_masters         = 3
+  accepted_networks   = ["10.0.0.0/8"]
+  tags = {
+    owner = "me"
+  }
+}
+`
+  vars := []ModuleVariable{
+    {"num_masters", "string", "Specify the amount of masters", nil},
+  }
+
+  updated, extracted, err := ExtractHCLVariables([]byte(content), []string{"module", "dcos-prod"}, "prod_", vars, []string{"ssh_public_key_file"})
+  if err != nil {
+    t.Fatalf("unexpected error: %s", err.Error())
+  }
+
+  // The module attributes, the kept ones and the interpolations stay in the
+  // module
+  expectedContent := `module "dcos-prod" {
+  source  = "dcos-terraform/dcos/aws"
+  version = "~> 0.2.0"
+
+  cluster_name        = "${var.prod_cluster_name}"
+  ssh_public_key_file = "cluster-prod-key.pub"
+  admin_ips           = ["${data.http.whatismyip-prod.body}/32"]
+  num_masters         = "${var.prod_num_masters}"
+  accepted_networks   = "${var.prod_accepted_networks}"
+  tags = "${var.prod_tags}"
+}
+`
+  if string(updated) != expectedContent {
+    t.Errorf("expected:\n%s\ngot:\n%s", expectedContent, string(updated))
+  }
+
+  // The types of the module variables win over the ones of the values
+  expectedVars := []ExtractedVariable{
+    {"prod_cluster_name", "string", "", `"prod"`},
+    {"prod_num_masters", "string", "Specify the amount of masters", "3"},
+    {"prod_accepted_networks", "list", "", `["10.0.0.0/8"]`},
+    {"prod_tags", "map", "", "{\n    owner = \"me\"\n  }"},
+  }
+  if !reflect.DeepEqual(extracted, expectedVars) {
+    t.Errorf("expected %#v, got %#v", expectedVars, extracted)
+  }
+}
+
+func TestExtractHCLVariablesMissingBlock(t *testing.T) {
+  _, _, err := ExtractHCLVariables([]byte(`module "dcos" {}`), []string{"module", "other"}, "", nil, nil)
+  if err == nil {
+    t.Errorf("expected an error for a missing block")
+  }
+}
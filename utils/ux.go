@@ -6,9 +6,11 @@ import (
   "io"
   "os"
   "strings"
+  "sync"
 
   . "github.com/logrusorgru/aurora"
   . "github.com/mattn/go-colorable"
+  "golang.org/x/crypto/ssh/terminal"
 )
 
 type OptionsPrinter interface {
@@ -19,19 +21,45 @@ type OptionsPrinter interface {
 var colorableStdout = NewColorableStdout()
 var colorableStderr = NewColorableStderr()
 
+var exitHandlers []func()
+var exitHandlersLock sync.Mutex
+
+/**
+ * Registers a function to call before the process exits through `Exit` or
+ * `FatalError`. The handlers are called in the reverse order of registration.
+ */
+func AtExit(handler func()) {
+  exitHandlersLock.Lock()
+  defer exitHandlersLock.Unlock()
+  exitHandlers = append(exitHandlers, handler)
+}
+
+/**
+ * Calls the registered exit handlers and exits with the given code
+ */
+func Exit(code int) {
+  exitHandlersLock.Lock()
+  handlers := exitHandlers
+  exitHandlers = nil
+  exitHandlersLock.Unlock()
+
+  for i := len(handlers) - 1; i >= 0; i-- {
+    handlers[i]()
+  }
+  os.Exit(code)
+}
+
 func FatalError(err error) {
-  colorableStderr.Write([]byte(fmt.Sprintf("%s %s\n", Red("Error:"), err.Error())))
-  os.Exit(1)
+  PrintEvent("error", "", nil, "%s", err.Error())
+  Exit(1)
 }
 
 func PrintInfo(format string, a ...interface{}) {
-  args := append([]interface{}{Cyan("Info: ")}, a...)
-  colorableStdout.Write([]byte(fmt.Sprintf("%s "+format+"\n", args...)))
+  PrintEvent("info", "", nil, format, a...)
 }
 
 func PrintWarning(format string, a ...interface{}) {
-  args := append([]interface{}{Bold(Yellow("Warn: "))}, a...)
-  colorableStdout.Write([]byte(fmt.Sprintf("%s "+format+"\n", args...)))
+  PrintEvent("warning", "", nil, format, a...)
 }
 
 func PrintHelp(cmd string, cmdline string, message []interface{}, opts OptionsPrinter) {
@@ -61,16 +89,76 @@ func PrintHelp(cmd string, cmdline string, message []interface{}, opts OptionsPr
 }
 
 func PrintMessage(message []interface{}) {
+  if IsJSONOutput() {
+    var lines []string
+    for _, line := range message {
+      lines = append(lines, fmt.Sprintf("%s", line))
+    }
+    PrintEvent("info", "message", nil, "%s", strings.Join(lines, "\n"))
+    return
+  }
+
   for _, line := range message {
     colorableStdout.Write([]byte(fmt.Sprintf("%s\n", line)))
   }
 }
 
+// A single reader, so that buffered input is not lost between prompts
+var stdinReader = bufio.NewReader(os.Stdin)
+
 func ReadPrompt(message string) string {
-  reader := bufio.NewReader(os.Stdin)
-  fmt.Print("%s: ", message)
-  text, _ := reader.ReadString('\n')
-  return text
+  fmt.Printf("%s: ", message)
+  text, err := stdinReader.ReadString('\n')
+  if err != nil && text == "" {
+    fmt.Println("")
+    FatalError(fmt.Errorf("No input available, aborting"))
+  }
+  return strings.TrimSpace(text)
+}
+
+/**
+ * Reads a secret without echoing it, when the input is a terminal. There is
+ * never a default value, so the secret is not shown in the prompt.
+ */
+func ReadPasswordPrompt(message string) string {
+  fd := int(os.Stdin.Fd())
+  if !terminal.IsTerminal(fd) {
+    return ReadPrompt(message)
+  }
+
+  fmt.Printf("%s: ", message)
+  text, err := terminal.ReadPassword(fd)
+  fmt.Println("")
+  if err != nil {
+    FatalError(fmt.Errorf("Could not read the input: %s", err.Error()))
+  }
+  return strings.TrimSpace(string(text))
+}
+
+/**
+ * Asks for a value until the answer passes the validation function (if not
+ * nil). An empty answer selects the default value.
+ */
+func ReadPromptWithDefault(message string, defaultValue string, validate func(string) error) string {
+  if defaultValue != "" {
+    message = fmt.Sprintf("%s [%s]", message, defaultValue)
+  }
+
+  for {
+    ans := ReadPrompt(message)
+    if ans == "" {
+      ans = defaultValue
+    }
+    if validate == nil {
+      return ans
+    }
+
+    err := validate(ans)
+    if err == nil {
+      return ans
+    }
+    PrintWarning("%s", err.Error())
+  }
 }
 
 func ReadYN(message string) bool {
@@ -80,8 +168,8 @@ func ReadYN(message string) bool {
       return true
     }
     if ans == "n" || ans == "no" {
-      return true
+      return false
     }
-    fmt.Println("\nInvalid option please specify 'yes' or 'no'")
+    fmt.Println("Invalid option please specify 'yes' or 'no'")
   }
 }